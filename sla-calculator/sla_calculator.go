@@ -13,6 +13,14 @@ const (
 	STATE_UP = "up"
 	// STATE_OPEN is the string value of open uptime state.
 	STATE_OPEN = "open"
+	// FORMULA_SNMP is the formula name of CalculateSNMPAvailability.
+	FORMULA_SNMP = "snmp"
+	// FORMULA_UPTIME is the formula name of CalculateUptimeAvailability.
+	FORMULA_UPTIME = "uptime"
+	// FORMULA_SLA1 is the formula name of CalculateSLA1Availability.
+	FORMULA_SLA1 = "sla1"
+	// FORMULA_SLA2 is the formula name of CalculateSLA2Availability.
+	FORMULA_SLA2 = "sla2"
 )
 
 // UptimeSLACalculator calculates Uptime SLA parameters based on specified formulas.
@@ -23,6 +31,7 @@ type UptimeSLACalculator struct {
 	startTime           int64
 	endTime             int64
 	toleranceDeltaRatio float64
+	clockDrift          *ClockDrift
 }
 
 func checkArguments(startTime, endTime int64, timestamps []int64, uptimeValues []int, toleranceDeltaRatio float64, exceptions []bool) error {
@@ -78,18 +87,23 @@ func NewUptimeSLACalculator(startTime, endTime int64, timestamps []int64, uptime
 		return nil, err
 	}
 	return &UptimeSLACalculator{
-		castSliceIntToInt64(uptimeValues),
-		timestamps,
-		exceptions,
-		startTime,
-		endTime,
-		toleranceDeltaRatio,
+		uptimeValues:        castSliceIntToInt64(uptimeValues),
+		timestamps:          timestamps,
+		exceptions:          exceptions,
+		startTime:           startTime,
+		endTime:             endTime,
+		toleranceDeltaRatio: toleranceDeltaRatio,
 	}, nil
 }
 
 // CalculateSNMPAvailability returns the availability value (SLA) based on
 // the existence of the data in each timestamp.
 func (u *UptimeSLACalculator) CalculateSNMPAvailability() float64 {
+	deltaTimeStamps, countedVals := u.snmpCountedVals()
+	return calcAvailability(deltaTimeStamps, countedVals)
+}
+
+func (u *UptimeSLACalculator) snmpCountedVals() ([]int64, []int64) {
 	timestamps := u.timestamps
 	uptimeValues := u.uptimeValues
 	startTime := u.startTime
@@ -117,12 +131,7 @@ func (u *UptimeSLACalculator) CalculateSNMPAvailability() float64 {
 		deltaTimeStamps = append(deltaTimeStamps, delta)
 		countedVals = append(countedVals, 0)
 	}
-	var sumCountedVal, sumDeltaTimestamp int64
-	for i := range countedVals {
-		sumCountedVal += countedVals[i]
-		sumDeltaTimestamp += deltaTimeStamps[i]
-	}
-	return float64(sumCountedVal) / float64(sumDeltaTimestamp)
+	return deltaTimeStamps, countedVals
 }
 
 func transformToSpreadedUptime(startTime, endTime int64, timestamps []int64, uptimeValues []int64, toleranceDeltaRatio float64) (deltaTimeStamps, countedVals []int64) {
@@ -171,6 +180,11 @@ func transformToSpreadedUptime(startTime, endTime int64, timestamps []int64, upt
 // the uptime value on each timestamp, it figures the availability of a device is UP
 // regardless the connectivity state.
 func (u *UptimeSLACalculator) CalculateUptimeAvailability() float64 {
+	deltaTimeStamps, countedVals := u.uptimeCountedVals()
+	return calcAvailability(deltaTimeStamps, countedVals)
+}
+
+func (u *UptimeSLACalculator) uptimeCountedVals() ([]int64, []int64) {
 	timestamps := u.timestamps
 	uptimeValues := u.uptimeValues
 	startTime := u.startTime
@@ -181,12 +195,7 @@ func (u *UptimeSLACalculator) CalculateUptimeAvailability() float64 {
 		deltaTimeStamps = append(deltaTimeStamps, delta)
 		countedVals = append(countedVals, 0)
 	}
-	var sumCountedVal, sumDeltaTimestamp int64
-	for i := range countedVals {
-		sumCountedVal += countedVals[i]
-		sumDeltaTimestamp += deltaTimeStamps[i]
-	}
-	return float64(sumCountedVal) / float64(sumDeltaTimestamp)
+	return deltaTimeStamps, countedVals
 }
 
 // CalculateSLA1Availability returns the availability value (SLA) based on
@@ -194,6 +203,11 @@ func (u *UptimeSLACalculator) CalculateUptimeAvailability() float64 {
 // while also regarding the device state (it counts as down if device is up but the
 // the connectivity is down, other scenarios counted as up)
 func (u *UptimeSLACalculator) CalculateSLA1Availability() float64 {
+	deltaTimeStamps, countedVals := u.sla1CountedVals()
+	return calcAvailability(deltaTimeStamps, countedVals)
+}
+
+func (u *UptimeSLACalculator) sla1CountedVals() ([]int64, []int64) {
 	timestamps := u.timestamps
 	uptimeValues := u.uptimeValues
 	startTime := u.startTime
@@ -226,18 +240,18 @@ func (u *UptimeSLACalculator) CalculateSLA1Availability() float64 {
 		deltaTimeStamps = append(deltaTimeStamps, delta)
 		countedVals = append(countedVals, 0)
 	}
-	var sumCountedVal, sumDeltaTimestamp int64
-	for i := range countedVals {
-		sumCountedVal += countedVals[i]
-		sumDeltaTimestamp += deltaTimeStamps[i]
-	}
-	return float64(sumCountedVal) / float64(sumDeltaTimestamp)
+	return deltaTimeStamps, countedVals
 }
 
 // CalculateSLA2Availability returns the availability value (SLA) based on
 // the Uptime SLA 1 Availability and also regards the exception (proved by justification
 // document in the real life , ie: scheduled maintenance.)
 func (u *UptimeSLACalculator) CalculateSLA2Availability() float64 {
+	deltaTimeStamps, countedVals := u.sla2CountedVals()
+	return calcAvailability(deltaTimeStamps, countedVals)
+}
+
+func (u *UptimeSLACalculator) sla2CountedVals() ([]int64, []int64) {
 	timestamps := u.timestamps
 	uptimeValues := u.uptimeValues
 	exceptions := u.exceptions
@@ -281,12 +295,7 @@ func (u *UptimeSLACalculator) CalculateSLA2Availability() float64 {
 		deltaTimeStamps = append(deltaTimeStamps, delta)
 		countedVals = append(countedVals, 0)
 	}
-	var sumCountedVal, sumDeltaTimestamp int64
-	for i := range countedVals {
-		sumCountedVal += countedVals[i]
-		sumDeltaTimestamp += deltaTimeStamps[i]
-	}
-	return float64(sumCountedVal) / float64(sumDeltaTimestamp)
+	return deltaTimeStamps, countedVals
 }
 
 // GetUptimeStateSeriesData returns the state of every uptime series data either up, down, or open.
@@ -337,3 +346,44 @@ func (u *UptimeSLACalculator) GetTotalUptimeAndDowntime() (uptime, downtime int6
 	downtime = sumDeltaTimestamp - uptime
 	return uptime, downtime
 }
+
+func calcAvailability(deltaTimeStamps, countedVals []int64) float64 {
+	var sumCountedVal, sumDeltaTimestamp int64
+	for i := range countedVals {
+		sumCountedVal += countedVals[i]
+		sumDeltaTimestamp += deltaTimeStamps[i]
+	}
+	return float64(sumCountedVal) / float64(sumDeltaTimestamp)
+}
+
+// countedIntervals returns the counted intervals of the formula, on which the timeline based helpers work.
+// The counted uptime exceeding its interval, which transformToSpreadedUptime leaves within the tolerance,
+// is carried back to the earlier intervals, so the timeline keeps the same total uptime as the formula.
+// Only the uptime carried back beyond the first interval is lost, ie: an availability above 1 is clipped to 1.
+// Nothing is carried back with a zero tolerance, as it disables the spreading.
+func (u *UptimeSLACalculator) countedIntervals(formula string) ([]FormulaInterval, error) {
+	result, err := u.CalculateFormula(formula)
+	if err != nil {
+		return nil, err
+	}
+	intervals := append([]FormulaInterval{}, result.Intervals...)
+	if u.toleranceDeltaRatio == 0 {
+		return intervals, nil
+	}
+	for i := len(intervals) - 1; i > 0; i-- {
+		if excess := intervals[i].Counted - (intervals[i].EndTime - intervals[i].StartTime); excess > 0 {
+			intervals[i].Counted -= excess
+			intervals[i-1].Counted += excess
+		}
+	}
+	return intervals, nil
+}
+
+// availableFormulas returns the generic formulas which can be calculated by the calculator.
+func (u *UptimeSLACalculator) availableFormulas() []string {
+	formulas := []string{FORMULA_SNMP, FORMULA_UPTIME, FORMULA_SLA1}
+	if u.exceptions != nil {
+		formulas = append(formulas, FORMULA_SLA2)
+	}
+	return formulas
+}
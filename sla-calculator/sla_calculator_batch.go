@@ -0,0 +1,175 @@
+package slacalculator
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// BatchInput is a series to be calculated by RunBatch.
+type BatchInput struct {
+	ID                  string
+	StartTime           int64
+	EndTime             int64
+	Series              UptimeSeries
+	ToleranceDeltaRatio float64
+}
+
+// BatchResult is the calculation result of a BatchInput.
+type BatchResult struct {
+	// Index is the position of the input in the stream.
+	Index   int
+	ID      string
+	Results map[string]*FormulaResult
+	Err     error
+}
+
+// BatchOptions configures RunBatch.
+type BatchOptions struct {
+	// Workers is the size of the worker pool, zero means the amount of CPUs.
+	Workers int
+	// Formulas lists the formulas to be calculated, empty means every generic formula the input supports.
+	Formulas []string
+	// Ordered emits the results in the input order instead of as they complete.
+	Ordered bool
+}
+
+type batchJob struct {
+	index int
+	input BatchInput
+}
+
+// RunBatch calculates the stream of inputs on a bounded worker pool. An input failure is reported
+// in its result without stopping the batch. The result channel is closed once every input is
+// calculated, or once the context is done, in which case the remaining inputs are not calculated.
+func RunBatch(ctx context.Context, inputs <-chan BatchInput, options BatchOptions) <-chan BatchResult {
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	jobs := make(chan batchJob)
+	completed := make(chan BatchResult, workers)
+	// Dispatch the inputs
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			select {
+			case <-ctx.Done():
+				return
+			case input, ok := <-inputs:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case jobs <- batchJob{index, input}:
+				}
+			}
+		}
+	}()
+	// Calculate on the worker pool
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				result := calculateBatchInput(job.index, job.input, options.Formulas)
+				select {
+				case <-ctx.Done():
+					return
+				case completed <- result:
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(completed)
+	}()
+	if !options.Ordered {
+		return completed
+	}
+	// Reorder the completed results
+	ordered := make(chan BatchResult, workers)
+	go func() {
+		defer close(ordered)
+		pending := map[int]BatchResult{}
+		next := 0
+		for result := range completed {
+			pending[result.Index] = result
+			for {
+				result, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				select {
+				case <-ctx.Done():
+					return
+				case ordered <- result:
+				}
+				next++
+			}
+		}
+	}()
+	return ordered
+}
+
+// RunBatchSlice calculates the inputs by RunBatch and returns the results in the input order.
+func RunBatchSlice(ctx context.Context, inputs []BatchInput, options BatchOptions) ([]BatchResult, error) {
+	stream := make(chan BatchInput)
+	go func() {
+		defer close(stream)
+		for _, input := range inputs {
+			select {
+			case <-ctx.Done():
+				return
+			case stream <- input:
+			}
+		}
+	}()
+	options.Ordered = true
+	results := []BatchResult{}
+	for result := range RunBatch(ctx, stream, options) {
+		results = append(results, result)
+	}
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+func calculateBatchInput(index int, input BatchInput, formulas []string) (result BatchResult) {
+	result = BatchResult{Index: index, ID: input.ID}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Results = nil
+			result.Err = fmt.Errorf("calculation of %v panics: %v", input.ID, r)
+		}
+	}()
+	calc, err := input.Series.NewCalculator(input.StartTime, input.EndTime, input.ToleranceDeltaRatio)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if len(formulas) <= 0 {
+		formulas = calc.availableFormulas()
+	}
+	result.Results = map[string]*FormulaResult{}
+	for _, formula := range formulas {
+		formulaResult, err := calc.CalculateFormula(formula)
+		if err != nil {
+			result.Results = nil
+			result.Err = fmt.Errorf("failed on calculating %v: %v", formula, err)
+			return result
+		}
+		result.Results[formula] = formulaResult
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package slacalculator_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestRunBatch(t *testing.T) {
+	uptimeVals := []int{}
+	timestamps := []int64{}
+	exceptions := []bool{}
+	for _, val := range uptimeSeriesData {
+		uptimeVals = append(uptimeVals, val.Value)
+		timestamps = append(timestamps, val.Timestamp)
+		exceptions = append(exceptions, val.Exception)
+	}
+	series := slacalc.UptimeSeries{Timestamps: timestamps, UptimeValues: uptimeVals, Exceptions: exceptions}
+	inputs := []slacalc.BatchInput{}
+	for i := 0; i < 100; i++ {
+		input := slacalc.BatchInput{
+			ID:                  "site",
+			StartTime:           startTime,
+			EndTime:             endTime + 100,
+			Series:              series,
+			ToleranceDeltaRatio: toleranceDeltaRatio,
+		}
+		if i%10 == 9 {
+			// Invalid period
+			input.StartTime = endTime
+		}
+		inputs = append(inputs, input)
+	}
+	t.Run("Ordered", func(t *testing.T) {
+		results, err := slacalc.RunBatchSlice(context.Background(), inputs, slacalc.BatchOptions{Workers: 4})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(results) != len(inputs) {
+			t.Fatalf("The amount of results is %v instead of %v", len(results), len(inputs))
+		}
+		for i, result := range results {
+			if result.Index != i {
+				t.Fatalf("The result %v has index %v", i, result.Index)
+			}
+			if i%10 == 9 {
+				if result.Err == nil {
+					t.Errorf("The result %v should carry an error", i)
+				}
+				continue
+			}
+			if result.Err != nil {
+				t.Fatalf("An Error should not be accoured: %v", result.Err)
+			}
+			if math.Abs(result.Results[slacalc.FORMULA_SLA2].Availability-expetedSLA2Availability) >= ACCURACY {
+				t.Errorf("The SLA 2 availability is %v instead of %v", result.Results[slacalc.FORMULA_SLA2].Availability, expetedSLA2Availability)
+			}
+		}
+	})
+	t.Run("Unordered", func(t *testing.T) {
+		stream := make(chan slacalc.BatchInput)
+		go func() {
+			defer close(stream)
+			for _, input := range inputs {
+				stream <- input
+			}
+		}()
+		seen := map[int]bool{}
+		for result := range slacalc.RunBatch(context.Background(), stream, slacalc.BatchOptions{Workers: 3, Formulas: []string{slacalc.FORMULA_UPTIME}}) {
+			seen[result.Index] = true
+			if result.Err == nil && len(result.Results) != 1 {
+				t.Errorf("The amount of formula results is %v instead of 1", len(result.Results))
+			}
+		}
+		if len(seen) != len(inputs) {
+			t.Errorf("The amount of results is %v instead of %v", len(seen), len(inputs))
+		}
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := slacalc.RunBatchSlice(ctx, inputs, slacalc.BatchOptions{Workers: 2})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if len(results) >= len(inputs) {
+			t.Errorf("The canceled batch should not calculate every input")
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package slacalculator
+
+import (
+	"math"
+)
+
+// ClockDrift explains the fitted drift rate between the device uptime and the poller clock.
+type ClockDrift struct {
+	// Rate is the device uptime seconds elapsed per poller second, 1 means no drift.
+	Rate float64
+	// Drift is the relative drift of the poller clock, ie: 0.001 means uptime runs 0.1% faster.
+	Drift float64
+	// Intervals is the amount of sample intervals used to fit the rate.
+	Intervals int
+	// Corrected tells whether the uptime values have been corrected by the rate.
+	Corrected bool
+}
+
+// Exceeds tells whether the absolute drift is greater than maxDrift, to flag a bad poller.
+func (d ClockDrift) Exceeds(maxDrift float64) bool {
+	return math.Abs(d.Drift) > maxDrift
+}
+
+// EstimateClockDrift fits the drift rate between uptime deltas and timestamp deltas
+// across the up runs, ie: consecutive samples in which the uptime counter increases.
+func (u *UptimeSLACalculator) EstimateClockDrift() ClockDrift {
+	timestamps := u.timestamps
+	uptimeValues := u.uptimeValues
+	// Least squares fit through the origin of uptime delta against timestamp delta
+	var sumProduct, sumSquare float64
+	intervals := 0
+	for i := 1; i < len(timestamps); i++ {
+		deltaTimestamp := timestamps[i] - timestamps[i-1]
+		if deltaTimestamp <= 0 || uptimeValues[i-1] <= 0 || uptimeValues[i] <= uptimeValues[i-1] {
+			continue
+		}
+		deltaUptime := uptimeValues[i] - uptimeValues[i-1]
+		sumProduct += float64(deltaUptime) * float64(deltaTimestamp)
+		sumSquare += float64(deltaTimestamp) * float64(deltaTimestamp)
+		intervals++
+	}
+	if intervals == 0 {
+		return ClockDrift{Rate: 1}
+	}
+	rate := sumProduct / sumSquare
+	return ClockDrift{
+		Rate:      rate,
+		Drift:     rate - 1,
+		Intervals: intervals,
+	}
+}
+
+// CorrectClockDrift returns a new calculator whose uptime values are scaled to the poller clock
+// by the estimated drift rate. The estimation is kept in the new calculator, see ClockDrift.
+func (u *UptimeSLACalculator) CorrectClockDrift() (*UptimeSLACalculator, ClockDrift) {
+	drift := u.EstimateClockDrift()
+	drift.Corrected = true
+	uptimeValues := []int64{}
+	for _, val := range u.uptimeValues {
+		if val <= 0 {
+			uptimeValues = append(uptimeValues, val)
+			continue
+		}
+		corrected := int64(math.Round(float64(val) / drift.Rate))
+		if corrected < 1 {
+			corrected = 1
+		}
+		uptimeValues = append(uptimeValues, corrected)
+	}
+	return &UptimeSLACalculator{
+		uptimeValues:        uptimeValues,
+		timestamps:          u.timestamps,
+		exceptions:          u.exceptions,
+		startTime:           u.startTime,
+		endTime:             u.endTime,
+		toleranceDeltaRatio: u.toleranceDeltaRatio,
+		clockDrift:          &drift,
+	}, drift
+}
+
+// ClockDrift returns the clock drift used to correct the uptime values,
+// or nil if the calculator is not corrected.
+func (u *UptimeSLACalculator) ClockDrift() *ClockDrift {
+	return u.clockDrift
+}
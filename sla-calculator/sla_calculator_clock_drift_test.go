@@ -0,0 +1,55 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestClockDrift(t *testing.T) {
+	// The poller clock is 2% slower than the device clock
+	timestamps := []int64{10100, 10200, 10300, 10400, 10500, 10600}
+	uptimeVals := []int{102, 204, 306, 0, 102, 204}
+	calc, err := slacalc.NewUptimeSLACalculator(10000, 10600, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	t.Run("EstimateClockDrift", func(t *testing.T) {
+		drift := calc.EstimateClockDrift()
+		if math.Abs(drift.Rate-1.02) >= ACCURACY {
+			t.Errorf("The estimated rate is %v instead of 1.02", drift.Rate)
+		}
+		if drift.Intervals != 3 {
+			t.Errorf("The amount of fitted intervals is %v instead of 3", drift.Intervals)
+		}
+		if !drift.Exceeds(0.01) || drift.Exceeds(0.05) {
+			t.Errorf("The drift %v should exceed 0.01 but not 0.05", drift.Drift)
+		}
+		if drift.Corrected {
+			t.Errorf("The estimation should not be corrected")
+		}
+	})
+	t.Run("CorrectClockDrift", func(t *testing.T) {
+		corrected, drift := calc.CorrectClockDrift()
+		if !drift.Corrected || corrected.ClockDrift() == nil {
+			t.Fatalf("The corrected calculator should keep the clock drift")
+		}
+		if calc.ClockDrift() != nil {
+			t.Errorf("The original calculator should not be corrected")
+		}
+		if math.Abs(corrected.EstimateClockDrift().Rate-1) >= ACCURACY {
+			t.Errorf("The corrected rate is %v instead of 1", corrected.EstimateClockDrift().Rate)
+		}
+	})
+	t.Run("No Up Run", func(t *testing.T) {
+		calc, err := slacalc.NewUptimeSLACalculator(10000, 10200, []int64{10100, 10200}, []int{0, 0}, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		drift := calc.EstimateClockDrift()
+		if drift.Rate != 1 || drift.Intervals != 0 {
+			t.Errorf("The estimation without up run is %+v instead of rate 1", drift)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package slacalculator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CompositeMode decides how the components of a composite service are combined.
+type CompositeMode int
+
+const (
+	// CompositeSerial is up only if every component is up, ie: CPE -> last mile -> PoP router.
+	CompositeSerial CompositeMode = iota
+	// CompositeParallel is up if any component is up, ie: primary and backup VSAT.
+	CompositeParallel
+	// CompositeKOfN is up if at least K components are up.
+	CompositeKOfN
+)
+
+// CompositeOptions configures CalculateCompositeAvailability.
+type CompositeOptions struct {
+	Mode    CompositeMode
+	Formula string
+	// K is the minimum amount of up components, used by CompositeKOfN.
+	K int
+}
+
+// CompositeInterval is an aligned time range in which every component keeps its state.
+type CompositeInterval struct {
+	StartTime int64
+	EndTime   int64
+	// UpCount is the amount of up components.
+	UpCount int
+	Up      bool
+}
+
+// CompositeResult is the availability of a composite service.
+type CompositeResult struct {
+	Mode         CompositeMode
+	Formula      string
+	K            int
+	Components   int
+	Availability float64
+	Uptime       int64
+	Downtime     int64
+	Intervals    []CompositeInterval
+}
+
+// CalculateCompositeAvailability combines the formula timeline of every calculator on aligned time intervals.
+// Every calculator should have the same start and end time.
+func CalculateCompositeAvailability(options CompositeOptions, calcs ...*UptimeSLACalculator) (*CompositeResult, error) {
+	if len(calcs) <= 0 {
+		return nil, fmt.Errorf("no component to be combined")
+	}
+	k := options.K
+	switch options.Mode {
+	case CompositeSerial:
+		k = len(calcs)
+	case CompositeParallel:
+		k = 1
+	case CompositeKOfN:
+		if k <= 0 || k > len(calcs) {
+			return nil, fmt.Errorf("k should be between 1 and %v: %v", len(calcs), k)
+		}
+	default:
+		return nil, fmt.Errorf("unknown composite mode: %v", options.Mode)
+	}
+	startTime, endTime := calcs[0].startTime, calcs[0].endTime
+	timelines := [][]timeSpan{}
+	boundaries := []int64{}
+	for i, calc := range calcs {
+		if calc.startTime != startTime || calc.endTime != endTime {
+			return nil, fmt.Errorf("period of component %v is different: %v-%v instead of %v-%v", i, calc.startTime, calc.endTime, startTime, endTime)
+		}
+		intervals, err := calc.countedIntervals(options.Formula)
+		if err != nil {
+			return nil, fmt.Errorf("failed on calculating component %v: %v", i, err)
+		}
+		timeline := timelineOfIntervals(intervals)
+		for _, span := range timeline {
+			boundaries = append(boundaries, span.start, span.end)
+		}
+		timelines = append(timelines, timeline)
+	}
+	boundaries = append(boundaries, startTime, endTime)
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i] < boundaries[j]
+	})
+	result := CompositeResult{
+		Mode:       options.Mode,
+		Formula:    options.Formula,
+		K:          k,
+		Components: len(calcs),
+	}
+	cursors := make([]int, len(timelines))
+	for i := 1; i < len(boundaries); i++ {
+		start, end := boundaries[i-1], boundaries[i]
+		if start == end {
+			continue
+		}
+		upCount := 0
+		for j, timeline := range timelines {
+			for cursors[j] < len(timeline) && timeline[cursors[j]].end <= start {
+				cursors[j]++
+			}
+			if cursors[j] < len(timeline) && timeline[cursors[j]].start <= start && timeline[cursors[j]].up {
+				upCount++
+			}
+		}
+		up := upCount >= k
+		if up {
+			result.Uptime += end - start
+		}
+		if last := len(result.Intervals) - 1; last >= 0 && result.Intervals[last].UpCount == upCount {
+			result.Intervals[last].EndTime = end
+			continue
+		}
+		result.Intervals = append(result.Intervals, CompositeInterval{start, end, upCount, up})
+	}
+	result.Downtime = endTime - startTime - result.Uptime
+	result.Availability = float64(result.Uptime) / float64(endTime-startTime)
+	return &result, nil
+}
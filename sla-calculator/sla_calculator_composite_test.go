@@ -0,0 +1,85 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestCalculateCompositeAvailability(t *testing.T) {
+	timestamps := []int64{100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}
+	components := [][]int{
+		// down 100-200
+		{100, 0, 100, 200, 300, 400, 500, 600, 700, 800},
+		// down 100-300
+		{100, 0, 0, 100, 200, 300, 400, 500, 600, 700},
+		// down 500-600
+		{100, 200, 300, 400, 500, 0, 100, 200, 300, 400},
+	}
+	calcs := []*slacalc.UptimeSLACalculator{}
+	for _, uptimeVals := range components {
+		calc, err := slacalc.NewUptimeSLACalculator(0, 1000, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		calcs = append(calcs, calc)
+	}
+	cases := []struct {
+		name         string
+		options      slacalc.CompositeOptions
+		availability float64
+	}{
+		{"Serial", slacalc.CompositeOptions{Mode: slacalc.CompositeSerial, Formula: slacalc.FORMULA_SNMP}, 0.7},
+		{"Parallel", slacalc.CompositeOptions{Mode: slacalc.CompositeParallel, Formula: slacalc.FORMULA_SNMP}, 1.0},
+		{"2 of 3", slacalc.CompositeOptions{Mode: slacalc.CompositeKOfN, K: 2, Formula: slacalc.FORMULA_SNMP}, 0.9},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := slacalc.CalculateCompositeAvailability(c.options, calcs...)
+			if err != nil {
+				t.Fatalf("An Error should not be accoured: %v", err)
+			}
+			if math.Abs(result.Availability-c.availability) >= ACCURACY {
+				t.Errorf("The composite availability is %v instead of %v", result.Availability, c.availability)
+			}
+			if result.Uptime+result.Downtime != 1000 {
+				t.Errorf("The uptime and downtime do not cover the period")
+			}
+		})
+	}
+	t.Run("Intervals", func(t *testing.T) {
+		result, err := slacalc.CalculateCompositeAvailability(slacalc.CompositeOptions{Mode: slacalc.CompositeSerial, Formula: slacalc.FORMULA_SNMP}, calcs...)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		expected := []slacalc.CompositeInterval{
+			{StartTime: 0, EndTime: 100, UpCount: 3, Up: true},
+			{StartTime: 100, EndTime: 200, UpCount: 1, Up: false},
+			{StartTime: 200, EndTime: 300, UpCount: 2, Up: false},
+			{StartTime: 300, EndTime: 500, UpCount: 3, Up: true},
+			{StartTime: 500, EndTime: 600, UpCount: 2, Up: false},
+			{StartTime: 600, EndTime: 1000, UpCount: 3, Up: true},
+		}
+		if len(result.Intervals) != len(expected) {
+			t.Fatalf("The intervals are %+v instead of %+v", result.Intervals, expected)
+		}
+		for i := range expected {
+			if result.Intervals[i] != expected[i] {
+				t.Errorf("The interval %v is %+v instead of %+v", i, result.Intervals[i], expected[i])
+			}
+		}
+	})
+	t.Run("Invalid Composite", func(t *testing.T) {
+		if _, err := slacalc.CalculateCompositeAvailability(slacalc.CompositeOptions{Mode: slacalc.CompositeKOfN, K: 4, Formula: slacalc.FORMULA_SNMP}, calcs...); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		other, err := slacalc.NewUptimeSLACalculator(0, 1100, timestamps, components[0], toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if _, err := slacalc.CalculateCompositeAvailability(slacalc.CompositeOptions{Formula: slacalc.FORMULA_SNMP}, calcs[0], other); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package slacalculator
+
+import "fmt"
+
+// Contract declares how the availability of a customer service is measured and judged.
+type Contract struct {
+	Name    string
+	Formula string
+	// Target is the availability target, ie: 0.995 for 99.5%.
+	Target float64
+	// PeriodStart and PeriodEnd are the measurement period, zero means the calculator period.
+	PeriodStart int64
+	PeriodEnd   int64
+	// ToleranceDeltaRatio overrides the tolerance of the calculator when it is set.
+	ToleranceDeltaRatio *float64
+	// GracePeriod is the first seconds of every outage which are not charged.
+	GracePeriod int64
+	// ExcludedCategories lists the exclusion categories which are not charged, ie: maintenance.
+	ExcludedCategories []string
+}
+
+// Exclusion is a time range of a category which may be excluded by a contract, ie: scheduled maintenance.
+type Exclusion struct {
+	Category  string
+	StartTime int64
+	EndTime   int64
+}
+
+// ContractEvaluation is the compliance verdict of a contract along with its supporting numbers.
+type ContractEvaluation struct {
+	Contract    Contract
+	PeriodStart int64
+	PeriodEnd   int64
+	// Availability is the formula availability within the period before applying grace period and exclusions.
+	Availability float64
+	// AdjustedAvailability is the contract availability after applying grace period and exclusions.
+	AdjustedAvailability float64
+	Downtime             int64
+	ExcludedDowntime     int64
+	GraceDowntime        int64
+	ChargedDowntime      int64
+	Outages              int
+	Compliant            bool
+	// Margin is the adjusted availability minus the target, negative when it is not compliant.
+	Margin float64
+}
+
+func (c Contract) validate() error {
+	if c.Target < 0 || c.Target > 1 {
+		return fmt.Errorf("target should be between 0 and 1: %v", c.Target)
+	}
+	if c.ToleranceDeltaRatio != nil && (*c.ToleranceDeltaRatio < 0 || *c.ToleranceDeltaRatio > 1) {
+		return fmt.Errorf("tolerance ratio value should be setted between 0 to 1: %v", *c.ToleranceDeltaRatio)
+	}
+	if c.GracePeriod < 0 {
+		return fmt.Errorf("grace period should not be less than 0: %v", c.GracePeriod)
+	}
+	return nil
+}
+
+func (c Contract) excludes(category string) bool {
+	for _, excludedCategory := range c.ExcludedCategories {
+		if excludedCategory == category {
+			return true
+		}
+	}
+	return false
+}
+
+// withTolerance returns a copy of the calculator using another tolerance delta ratio.
+func (u *UptimeSLACalculator) withTolerance(toleranceDeltaRatio float64) *UptimeSLACalculator {
+	calc := *u
+	calc.toleranceDeltaRatio = toleranceDeltaRatio
+	return &calc
+}
+
+// Evaluate evaluates the contract against the calculator. The exclusions whose category is excluded by
+// the contract are not charged, then the grace period is applied to the charged part of every outage.
+func (c Contract) Evaluate(calc *UptimeSLACalculator, exclusions []Exclusion) (*ContractEvaluation, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	periodStart, periodEnd := c.PeriodStart, c.PeriodEnd
+	if periodStart == 0 && periodEnd == 0 {
+		periodStart, periodEnd = calc.startTime, calc.endTime
+	}
+	if periodStart < calc.startTime || periodEnd > calc.endTime || periodStart >= periodEnd {
+		return nil, fmt.Errorf("period %v-%v is not within the calculator period %v-%v", periodStart, periodEnd, calc.startTime, calc.endTime)
+	}
+	if c.ToleranceDeltaRatio != nil {
+		calc = calc.withTolerance(*c.ToleranceDeltaRatio)
+	}
+	intervals, err := calc.countedIntervals(c.Formula)
+	if err != nil {
+		return nil, err
+	}
+	excludedRanges := [][2]int64{}
+	for _, exclusion := range exclusions {
+		if c.excludes(exclusion.Category) {
+			excludedRanges = append(excludedRanges, [2]int64{exclusion.StartTime, exclusion.EndTime})
+		}
+	}
+	excludedRanges = mergeRanges(excludedRanges)
+	evaluation := ContractEvaluation{
+		Contract:    c,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	}
+	for _, span := range clipTimeline(timelineOfIntervals(intervals), periodStart, periodEnd) {
+		if span.up {
+			continue
+		}
+		evaluation.Outages++
+		evaluation.Downtime += span.end - span.start
+		grace := c.GracePeriod
+		for _, charged := range subtractRanges(span.start, span.end, excludedRanges) {
+			duration := charged[1] - charged[0]
+			if grace > duration {
+				grace -= duration
+				evaluation.GraceDowntime += duration
+				continue
+			}
+			evaluation.GraceDowntime += grace
+			evaluation.ChargedDowntime += duration - grace
+			grace = 0
+		}
+	}
+	evaluation.ExcludedDowntime = evaluation.Downtime - evaluation.GraceDowntime - evaluation.ChargedDowntime
+	periodDuration := float64(periodEnd - periodStart)
+	evaluation.Availability = 1 - float64(evaluation.Downtime)/periodDuration
+	evaluation.AdjustedAvailability = 1 - float64(evaluation.ChargedDowntime)/periodDuration
+	evaluation.Margin = evaluation.AdjustedAvailability - c.Target
+	evaluation.Compliant = evaluation.Margin >= 0
+	return &evaluation, nil
+}
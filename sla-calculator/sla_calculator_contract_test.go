@@ -0,0 +1,97 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestContractEvaluate(t *testing.T) {
+	timestamps := []int64{100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}
+	uptimeVals := []int{100, 200, 300, 400, 0, 0, 100, 200, 300, 400}
+	calc, err := slacalc.NewUptimeSLACalculator(0, 1000, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	contract := slacalc.Contract{
+		Name:               "Enterprise 90",
+		Formula:            slacalc.FORMULA_SNMP,
+		Target:             0.9,
+		GracePeriod:        50,
+		ExcludedCategories: []string{"maintenance"},
+	}
+	exclusions := []slacalc.Exclusion{
+		{Category: "maintenance", StartTime: 500, EndTime: 550},
+		{Category: "force majeure", StartTime: 0, EndTime: 1000},
+	}
+	evaluation, err := contract.Evaluate(calc, exclusions)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	if evaluation.Outages != 1 || evaluation.Downtime != 200 {
+		t.Errorf("The outage is %v with %v seconds downtime instead of 1 with 200 seconds", evaluation.Outages, evaluation.Downtime)
+	}
+	if evaluation.ExcludedDowntime != 50 || evaluation.GraceDowntime != 50 || evaluation.ChargedDowntime != 100 {
+		t.Errorf("The excluded, grace and charged downtime are %v, %v and %v instead of 50, 50 and 100",
+			evaluation.ExcludedDowntime, evaluation.GraceDowntime, evaluation.ChargedDowntime)
+	}
+	if math.Abs(evaluation.Availability-0.8) >= ACCURACY {
+		t.Errorf("The availability is %v instead of 0.8", evaluation.Availability)
+	}
+	if math.Abs(evaluation.AdjustedAvailability-0.9) >= ACCURACY || !evaluation.Compliant {
+		t.Errorf("The adjusted availability is %v and should be compliant", evaluation.AdjustedAvailability)
+	}
+	t.Run("Measurement Period", func(t *testing.T) {
+		contract := contract
+		contract.PeriodStart, contract.PeriodEnd = 0, 500
+		contract.Target = 0.95
+		evaluation, err := contract.Evaluate(calc, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if evaluation.ChargedDowntime != 50 || evaluation.Compliant {
+			t.Errorf("The charged downtime is %v and should not be compliant", evaluation.ChargedDowntime)
+		}
+	})
+	t.Run("Zero Tolerance", func(t *testing.T) {
+		// The uptime after the timeout is spreaded back to the timeout only with a non zero tolerance
+		calc, err := slacalc.NewUptimeSLACalculator(0, 500, []int64{100, 200, 300, 400, 500}, []int{100, 200, 0, 400, 500}, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		contract := slacalc.Contract{Formula: slacalc.FORMULA_UPTIME, Target: 0.9}
+		evaluation, err := contract.Evaluate(calc, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if evaluation.Downtime != 0 {
+			t.Errorf("The downtime is %v instead of 0", evaluation.Downtime)
+		}
+		zeroTolerance := 0.0
+		contract.ToleranceDeltaRatio = &zeroTolerance
+		evaluation, err = contract.Evaluate(calc, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if evaluation.Downtime != 100 {
+			t.Errorf("The downtime is %v instead of 100", evaluation.Downtime)
+		}
+		invalidTolerance := 1.5
+		contract.ToleranceDeltaRatio = &invalidTolerance
+		if _, err := contract.Evaluate(calc, nil); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+	t.Run("Invalid Contract", func(t *testing.T) {
+		contract := contract
+		contract.PeriodStart, contract.PeriodEnd = 0, 2000
+		if _, err := contract.Evaluate(calc, nil); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		contract = slacalc.Contract{Formula: "unknown", Target: 0.9}
+		if _, err := contract.Evaluate(calc, nil); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
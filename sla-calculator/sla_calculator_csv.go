@@ -0,0 +1,322 @@
+package slacalculator
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CSV_TIMESTAMP_UNIX is the timestamp format of unix seconds.
+	CSV_TIMESTAMP_UNIX = "unix"
+	// CSV_TIMESTAMP_UNIX_MS is the timestamp format of unix milliseconds.
+	CSV_TIMESTAMP_UNIX_MS = "unixms"
+)
+
+// CSVOptions configures the CSV reader. A column is referred by its header name when the CSV
+// has a header, otherwise by its zero-based index, ie: "0".
+type CSVOptions struct {
+	// Delimiter is the field delimiter, zero means comma.
+	Delimiter rune
+	HasHeader bool
+	// TimestampColumn is the column of the sample timestamp.
+	TimestampColumn string
+	// TimestampFormat is CSV_TIMESTAMP_UNIX (default), CSV_TIMESTAMP_UNIX_MS, or a time layout.
+	TimestampFormat string
+	// Location is used by a time layout without zone, nil means UTC.
+	Location *time.Location
+	// UptimeColumns builds one series per column, keyed by the column, ie: one column per device.
+	UptimeColumns []string
+	// DeviceColumn and UptimeColumn build one series per device id, used when DeviceColumn is set.
+	DeviceColumn string
+	UptimeColumn string
+	// ExceptionColumn is the optional column of the exception flag, ie: true, 1 or yes.
+	ExceptionColumn string
+	// UptimeScale multiplies the uptime values, ie: 0.01 for timeticks, zero means 1.
+	UptimeScale float64
+	// DuplicateRule is used to normalize the series by NewCalculatorsFromCSV.
+	DuplicateRule DuplicateRule
+}
+
+func resolveCSVColumn(column string, header []string) (int, error) {
+	for i, name := range header {
+		if name == column {
+			return i, nil
+		}
+	}
+	index, err := strconv.Atoi(column)
+	if err != nil || index < 0 {
+		return -1, fmt.Errorf("unknown column: %v", column)
+	}
+	return index, nil
+}
+
+func parseCSVTimestamp(value string, options CSVOptions) (int64, error) {
+	value = strings.TrimSpace(value)
+	switch options.TimestampFormat {
+	case "", CSV_TIMESTAMP_UNIX:
+		timestamp, err := strconv.ParseFloat(value, 64)
+		return int64(timestamp), err
+	case CSV_TIMESTAMP_UNIX_MS:
+		timestamp, err := strconv.ParseFloat(value, 64)
+		return int64(timestamp / 1000), err
+	}
+	location := options.Location
+	if location == nil {
+		location = time.UTC
+	}
+	timestamp, err := time.ParseInLocation(options.TimestampFormat, value, location)
+	return timestamp.Unix(), err
+}
+
+func parseCSVUptime(value string, scale float64) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	uptime, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if scale != 0 {
+		uptime *= scale
+	}
+	return int(math.Round(uptime)), nil
+}
+
+func parseCSVBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "y":
+		return true
+	}
+	return false
+}
+
+// ReadCSVSeries reads the CSV into series keyed by the uptime column or by the device id.
+func ReadCSVSeries(r io.Reader, options CSVOptions) (map[string]UptimeSeries, error) {
+	reader := csv.NewReader(r)
+	if options.Delimiter != 0 {
+		reader.Comma = options.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header := []string{}
+	if options.HasHeader {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed on reading csv header: %v", err)
+		}
+		header = record
+	}
+	timestampColumn, err := resolveCSVColumn(options.TimestampColumn, header)
+	if err != nil {
+		return nil, err
+	}
+	exceptionColumn := -1
+	if options.ExceptionColumn != "" {
+		if exceptionColumn, err = resolveCSVColumn(options.ExceptionColumn, header); err != nil {
+			return nil, err
+		}
+	}
+	deviceColumn := -1
+	uptimeColumns := map[string]int{}
+	if options.DeviceColumn != "" {
+		if deviceColumn, err = resolveCSVColumn(options.DeviceColumn, header); err != nil {
+			return nil, err
+		}
+		if uptimeColumns[""], err = resolveCSVColumn(options.UptimeColumn, header); err != nil {
+			return nil, err
+		}
+	} else {
+		if len(options.UptimeColumns) <= 0 {
+			return nil, fmt.Errorf("no uptime column is configured")
+		}
+		for _, column := range options.UptimeColumns {
+			if uptimeColumns[column], err = resolveCSVColumn(column, header); err != nil {
+				return nil, err
+			}
+		}
+	}
+	seriesMap := map[string]UptimeSeries{}
+	line := 0
+	if options.HasHeader {
+		line++
+	}
+	for {
+		line++
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed on reading csv: %v", err)
+		}
+		field := func(column int) (string, error) {
+			if column >= len(record) {
+				return "", fmt.Errorf("line %v has no column %v", line, column)
+			}
+			return record[column], nil
+		}
+		value, err := field(timestampColumn)
+		if err != nil {
+			return nil, err
+		}
+		timestamp, err := parseCSVTimestamp(value, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed on parsing timestamp of line %v: %v", line, err)
+		}
+		exception := false
+		if exceptionColumn >= 0 {
+			value, err := field(exceptionColumn)
+			if err != nil {
+				return nil, err
+			}
+			exception = parseCSVBool(value)
+		}
+		for name, column := range uptimeColumns {
+			key := name
+			if deviceColumn >= 0 {
+				if key, err = field(deviceColumn); err != nil {
+					return nil, err
+				}
+			}
+			value, err := field(column)
+			if err != nil {
+				return nil, err
+			}
+			uptime, err := parseCSVUptime(value, options.UptimeScale)
+			if err != nil {
+				return nil, fmt.Errorf("failed on parsing uptime of line %v: %v", line, err)
+			}
+			series := seriesMap[key]
+			series.Timestamps = append(series.Timestamps, timestamp)
+			series.UptimeValues = append(series.UptimeValues, uptime)
+			// The series has exceptions only when the exception column is configured
+			if exceptionColumn >= 0 {
+				series.Exceptions = append(series.Exceptions, exception)
+			}
+			seriesMap[key] = series
+		}
+	}
+	return seriesMap, nil
+}
+
+// NewCalculatorsFromCSV reads the CSV by ReadCSVSeries, normalizes every series by the duplicate rule
+// of the options, then returns the uptime calculator object of every series.
+func NewCalculatorsFromCSV(r io.Reader, options CSVOptions, startTime, endTime int64, toleranceDeltaRatio float64) (map[string]*UptimeSLACalculator, error) {
+	seriesMap, err := ReadCSVSeries(r, options)
+	if err != nil {
+		return nil, err
+	}
+	calcs := map[string]*UptimeSLACalculator{}
+	for key, series := range seriesMap {
+		normalized, _, err := NormalizeSeries(series, options.DuplicateRule)
+		if err != nil {
+			return nil, fmt.Errorf("failed on normalizing %v: %v", key, err)
+		}
+		calc, err := normalized.NewCalculator(startTime, endTime, toleranceDeltaRatio)
+		if err != nil {
+			return nil, fmt.Errorf("failed on creating calculator of %v: %v", key, err)
+		}
+		calcs[key] = calc
+	}
+	return calcs, nil
+}
+
+func formatCSVFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatCSVInt(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+// WriteFormulaResultsCSV writes the formula results keyed by device and formula, sorted by both.
+func WriteFormulaResultsCSV(w io.Writer, results map[string]map[string]*FormulaResult) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{"device", "formula", "availability", "uptime", "downtime"}}
+	devices := []string{}
+	for device := range results {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+	for _, device := range devices {
+		formulas := []string{}
+		for formula := range results[device] {
+			formulas = append(formulas, formula)
+		}
+		sort.Strings(formulas)
+		for _, formula := range formulas {
+			result := results[device][formula]
+			records = append(records, []string{
+				device,
+				formula,
+				formatCSVFloat(result.Availability),
+				formatCSVInt(result.Uptime),
+				formatCSVInt(result.Downtime),
+			})
+		}
+	}
+	return writer.WriteAll(records)
+}
+
+// WriteStateIntervalsCSV writes the state intervals returned by GetUptimeStateIntervals.
+func WriteStateIntervalsCSV(w io.Writer, intervals []StateInterval) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{"start_time", "end_time", "state", "counted_seconds", "reason"}}
+	for _, interval := range intervals {
+		records = append(records, []string{
+			formatCSVInt(interval.StartTime),
+			formatCSVInt(interval.EndTime),
+			interval.State,
+			formatCSVInt(interval.CountedSeconds),
+			interval.Reason,
+		})
+	}
+	return writer.WriteAll(records)
+}
+
+var bakti1ChronologyCSVHeader = []string{"start_time", "end_time", "uptime_value", "status", "status_name", "link_failure_duration", "restitution_duration"}
+
+func bakti1ChronologyCSVRecord(chronology Bakti1UptimeChronology) []string {
+	return []string{
+		formatCSVInt(chronology.StartTimestamps),
+		formatCSVInt(chronology.EndTimestamps),
+		formatCSVInt(chronology.UptimeValue),
+		strconv.Itoa(chronology.Status),
+		BaktiStatusName(chronology.Status),
+		formatCSVInt(chronology.LinkFailureDuration),
+		formatCSVInt(chronology.RestitutionDuration),
+	}
+}
+
+// WriteBakti1ChronologiesCSV writes the BAKTI chronologies.
+func WriteBakti1ChronologiesCSV(w io.Writer, chronologies []Bakti1UptimeChronology) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{bakti1ChronologyCSVHeader}
+	for _, chronology := range chronologies {
+		records = append(records, bakti1ChronologyCSVRecord(chronology))
+	}
+	return writer.WriteAll(records)
+}
+
+// WriteBaktiSqfChronologiesCSV writes the BAKTI chronologies considering SQF data.
+func WriteBaktiSqfChronologiesCSV(w io.Writer, chronologies []BaktiSqfChronology) error {
+	writer := csv.NewWriter(w)
+	header := append(append([]string{}, bakti1ChronologyCSVHeader...), "sqf_status", "sqf_status_name", "sqf_value", "rain_quota")
+	records := [][]string{header}
+	for _, chronology := range chronologies {
+		records = append(records, append(bakti1ChronologyCSVRecord(chronology.Bakti1UptimeChronology),
+			strconv.Itoa(chronology.SqfStatus),
+			BaktiStatusName(chronology.SqfStatus),
+			formatCSVFloat(chronology.SqfValue),
+			formatCSVInt(chronology.RainQuota),
+		))
+	}
+	return writer.WriteAll(records)
+}
@@ -0,0 +1,145 @@
+package slacalculator_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestReadCSVSeries(t *testing.T) {
+	t.Run("Device Columns", func(t *testing.T) {
+		data := "time;router-a;router-b;maintenance\n" +
+			"2019-01-01 00:05:00;300;300;no\n" +
+			"2019-01-01 00:10:00;600;;no\n" +
+			"2019-01-01 00:15:00;900;900;yes\n"
+		seriesMap, err := slacalc.ReadCSVSeries(strings.NewReader(data), slacalc.CSVOptions{
+			Delimiter:       ';',
+			HasHeader:       true,
+			TimestampColumn: "time",
+			TimestampFormat: "2006-01-02 15:04:05",
+			UptimeColumns:   []string{"router-a", "router-b"},
+			ExceptionColumn: "maintenance",
+		})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(seriesMap) != 2 {
+			t.Fatalf("The amount of series is %v instead of 2", len(seriesMap))
+		}
+		series := seriesMap["router-b"]
+		if series.Timestamps[0] != 1546301100 || series.UptimeValues[1] != 0 || !series.Exceptions[2] {
+			t.Errorf("The router-b series is %+v", series)
+		}
+	})
+	t.Run("Device Id Key", func(t *testing.T) {
+		data := "1546301100000,site-1,30000\n" +
+			"1546301100000,site-2,60000\n" +
+			"1546301400000,site-1,60000\n" +
+			"1546301400000,site-2,90000\n" +
+			"1546301400000,site-2,90000\n"
+		calcs, err := slacalc.NewCalculatorsFromCSV(strings.NewReader(data), slacalc.CSVOptions{
+			TimestampColumn: "0",
+			TimestampFormat: slacalc.CSV_TIMESTAMP_UNIX_MS,
+			DeviceColumn:    "1",
+			UptimeColumn:    "2",
+			UptimeScale:     0.01,
+		}, 1546300800, 1546301400, toleranceDeltaRatio)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(calcs) != 2 {
+			t.Fatalf("The amount of calculators is %v instead of 2", len(calcs))
+		}
+		if avai := calcs["site-2"].CalculateUptimeAvailability(); math.Abs(avai-1) >= ACCURACY {
+			t.Errorf("The site-2 Uptime Availability is %v instead of 1", avai)
+		}
+		// SLA2 is not available without exception column
+		if calcs["site-2"].Exceptions() != nil {
+			t.Errorf("The exceptions should be nil without exception column")
+		}
+		if _, err := calcs["site-2"].CalculateFormula(slacalc.FORMULA_SLA2); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+	t.Run("Invalid CSV", func(t *testing.T) {
+		if _, err := slacalc.ReadCSVSeries(strings.NewReader("a,b\n1,2\n"), slacalc.CSVOptions{HasHeader: true, TimestampColumn: "c", UptimeColumns: []string{"b"}}); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := slacalc.ReadCSVSeries(strings.NewReader("x,2\n"), slacalc.CSVOptions{TimestampColumn: "0", UptimeColumns: []string{"1"}}); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := slacalc.ReadCSVSeries(strings.NewReader("1,2\n"), slacalc.CSVOptions{TimestampColumn: "0", UptimeColumns: []string{"5"}}); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	uptimeVals := []int{}
+	timestamps := []int64{}
+	exceptions := []bool{}
+	for _, val := range uptimeBaktiSeriesData {
+		uptimeVals = append(uptimeVals, val.Value)
+		timestamps = append(timestamps, val.Timestamp)
+		exceptions = append(exceptions, val.Exception)
+	}
+	calc, err := slacalc.NewUptimeSLACalculator(startTimeBakti, endTimeBakti, timestamps, uptimeVals, toleranceDeltaRatio, exceptions)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	t.Run("Formula Results", func(t *testing.T) {
+		result, err := calc.CalculateFormula(slacalc.FORMULA_UPTIME)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		buf := bytes.Buffer{}
+		err = slacalc.WriteFormulaResultsCSV(&buf, map[string]map[string]*slacalc.FormulaResult{"site-1": {slacalc.FORMULA_UPTIME: result}})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[1], "site-1,uptime,") {
+			t.Errorf("The written results are %q", lines)
+		}
+	})
+	t.Run("State Intervals", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		if err := slacalc.WriteStateIntervalsCSV(&buf, calc.GetUptimeStateIntervals()); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if !strings.Contains(buf.String(), "trailing open") {
+			t.Errorf("The written state intervals should contain the trailing open reason")
+		}
+	})
+	t.Run("Bakti Chronologies", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		chronologies := calc.ExplainBakti1Uptime()
+		if err := slacalc.WriteBakti1ChronologiesCSV(&buf, chronologies); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != len(chronologies)+1 || !strings.Contains(buf.String(), "link failure") {
+			t.Errorf("The written chronologies are %q", lines)
+		}
+		sqfTimestamps := []int64{}
+		sqfValues := []float64{}
+		for _, chronology := range chronologies {
+			sqfTimestamps = append(sqfTimestamps, chronology.StartTimestamps)
+			sqfValues = append(sqfValues, 5)
+		}
+		sqf, _, err := slacalc.CalcBaktiSqf(chronologies, sqfTimestamps, sqfValues, 100)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		buf.Reset()
+		if err := slacalc.WriteBaktiSqfChronologiesCSV(&buf, sqf.Chronologies); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if !strings.Contains(buf.String(), "sqf 3-7.1 quota") {
+			t.Errorf("The written sqf chronologies should contain the sqf status name")
+		}
+	})
+}
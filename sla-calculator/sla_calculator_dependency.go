@@ -0,0 +1,136 @@
+package slacalculator
+
+import "fmt"
+
+// AttributionPolicy decides what to do with the child downtime attributed to an upstream outage.
+type AttributionPolicy int
+
+const (
+	// AttributionExclude excludes the upstream downtime from the child SLA period.
+	AttributionExclude AttributionPolicy = iota
+	// AttributionReport keeps the child SLA as is and reports the upstream downtime separately.
+	AttributionReport
+)
+
+// DependencyGraph holds the calculators of a topology and their parent-child dependencies.
+type DependencyGraph struct {
+	calcs   map[string]*UptimeSLACalculator
+	parents map[string][]string
+	nodes   []string
+}
+
+// NewDependencyGraph returns an empty dependency graph.
+func NewDependencyGraph() *DependencyGraph {
+	return &DependencyGraph{
+		calcs:   map[string]*UptimeSLACalculator{},
+		parents: map[string][]string{},
+	}
+}
+
+// AddNode adds the calculator of a node to the graph.
+func (g *DependencyGraph) AddNode(name string, calc *UptimeSLACalculator) error {
+	if calc == nil {
+		return fmt.Errorf("calculator of node %v is nil", name)
+	}
+	if _, ok := g.calcs[name]; ok {
+		return fmt.Errorf("node %v already exists", name)
+	}
+	g.calcs[name] = calc
+	g.nodes = append(g.nodes, name)
+	return nil
+}
+
+// AddDependency declares that the child depends on the parent, ie: a site depends on its PoP router.
+func (g *DependencyGraph) AddDependency(parent, child string) error {
+	if _, ok := g.calcs[parent]; !ok {
+		return fmt.Errorf("unknown parent node: %v", parent)
+	}
+	if _, ok := g.calcs[child]; !ok {
+		return fmt.Errorf("unknown child node: %v", child)
+	}
+	if parent == child || g.ancestors(parent)[child] {
+		return fmt.Errorf("dependency %v -> %v creates a cycle", parent, child)
+	}
+	g.parents[child] = append(g.parents[child], parent)
+	return nil
+}
+
+// ancestors returns every node the node depends on, directly or transitively.
+func (g *DependencyGraph) ancestors(node string) map[string]bool {
+	ancestors := map[string]bool{}
+	stack := append([]string{}, g.parents[node]...)
+	for len(stack) > 0 {
+		parent := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if ancestors[parent] {
+			continue
+		}
+		ancestors[parent] = true
+		stack = append(stack, g.parents[parent]...)
+	}
+	return ancestors
+}
+
+// AttributedAvailability is the availability of a node after attributing the upstream outages.
+type AttributedAvailability struct {
+	Node string
+	// RawAvailability is the formula availability before attribution.
+	RawAvailability float64
+	// Availability is the availability after applying the attribution policy.
+	Availability float64
+	// OwnDowntime is the downtime which does not overlap any upstream outage.
+	OwnDowntime int64
+	// UpstreamDowntime is the downtime overlapping an outage of any ancestor.
+	UpstreamDowntime int64
+}
+
+// AttributeDowntime attributes the downtime of every node which overlaps the downtime of any
+// of its ancestors to the upstream, then applies the policy on the node availability.
+func (g *DependencyGraph) AttributeDowntime(formula string, policy AttributionPolicy) (map[string]*AttributedAvailability, error) {
+	if policy != AttributionExclude && policy != AttributionReport {
+		return nil, fmt.Errorf("unknown attribution policy: %v", policy)
+	}
+	downtimes := map[string][][2]int64{}
+	for _, node := range g.nodes {
+		intervals, err := g.calcs[node].countedIntervals(formula)
+		if err != nil {
+			return nil, fmt.Errorf("failed on calculating node %v: %v", node, err)
+		}
+		downtime := [][2]int64{}
+		for _, span := range timelineOfIntervals(intervals) {
+			if !span.up {
+				downtime = append(downtime, [2]int64{span.start, span.end})
+			}
+		}
+		downtimes[node] = downtime
+	}
+	results := map[string]*AttributedAvailability{}
+	for _, node := range g.nodes {
+		calc := g.calcs[node]
+		upstream := [][2]int64{}
+		for ancestor := range g.ancestors(node) {
+			upstream = append(upstream, downtimes[ancestor]...)
+		}
+		upstream = mergeRanges(upstream)
+		result := AttributedAvailability{Node: node}
+		for _, downtime := range downtimes[node] {
+			for _, own := range subtractRanges(downtime[0], downtime[1], upstream) {
+				result.OwnDowntime += own[1] - own[0]
+			}
+			result.UpstreamDowntime += downtime[1] - downtime[0]
+		}
+		result.UpstreamDowntime -= result.OwnDowntime
+		periodDuration := calc.endTime - calc.startTime
+		result.RawAvailability = 1 - float64(result.OwnDowntime+result.UpstreamDowntime)/float64(periodDuration)
+		result.Availability = result.RawAvailability
+		if policy == AttributionExclude {
+			if remaining := periodDuration - result.UpstreamDowntime; remaining > 0 {
+				result.Availability = 1 - float64(result.OwnDowntime)/float64(remaining)
+			} else {
+				result.Availability = DEFAULT_FLOAT_VALUE
+			}
+		}
+		results[node] = &result
+	}
+	return results, nil
+}
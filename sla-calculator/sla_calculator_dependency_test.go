@@ -0,0 +1,79 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	timestamps := []int64{100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}
+	nodes := map[string][]int{
+		// down 100-200
+		"pop": {100, 0, 100, 200, 300, 400, 500, 600, 700, 800},
+		// down 0-200
+		"lastmile": {0, 0, 100, 200, 300, 400, 500, 600, 700, 800},
+		// down 0-100 and 400-500
+		"site": {0, 100, 200, 300, 0, 100, 200, 300, 400, 500},
+	}
+	newGraph := func(t *testing.T) *slacalc.DependencyGraph {
+		graph := slacalc.NewDependencyGraph()
+		for _, name := range []string{"pop", "lastmile", "site"} {
+			calc, err := slacalc.NewUptimeSLACalculator(0, 1000, timestamps, nodes[name], toleranceDeltaRatio, nil)
+			if err != nil {
+				t.Fatalf("An Error should not be accoured: %v", err)
+			}
+			if err := graph.AddNode(name, calc); err != nil {
+				t.Fatalf("An Error should not be accoured: %v", err)
+			}
+		}
+		if err := graph.AddDependency("pop", "lastmile"); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if err := graph.AddDependency("lastmile", "site"); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		return graph
+	}
+	t.Run("Exclude", func(t *testing.T) {
+		results, err := newGraph(t).AttributeDowntime(slacalc.FORMULA_SNMP, slacalc.AttributionExclude)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if results["pop"].UpstreamDowntime != 0 || results["pop"].OwnDowntime != 100 {
+			t.Errorf("The pop downtime is %+v instead of its own 100 seconds", results["pop"])
+		}
+		if results["lastmile"].UpstreamDowntime != 100 || results["lastmile"].OwnDowntime != 100 {
+			t.Errorf("The last mile downtime is %+v instead of 100 upstream and 100 own", results["lastmile"])
+		}
+		site := results["site"]
+		if site.UpstreamDowntime != 100 || site.OwnDowntime != 100 {
+			t.Errorf("The site downtime is %+v instead of 100 upstream and 100 own", site)
+		}
+		if math.Abs(site.RawAvailability-0.8) >= ACCURACY || math.Abs(site.Availability-0.8889) >= ACCURACY {
+			t.Errorf("The site availability is %v (raw %v) instead of 0.8889 (raw 0.8)", site.Availability, site.RawAvailability)
+		}
+	})
+	t.Run("Report", func(t *testing.T) {
+		results, err := newGraph(t).AttributeDowntime(slacalc.FORMULA_SNMP, slacalc.AttributionReport)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if results["site"].Availability != results["site"].RawAvailability || results["site"].UpstreamDowntime != 100 {
+			t.Errorf("The site should keep its raw availability and report the upstream downtime: %+v", results["site"])
+		}
+	})
+	t.Run("Invalid Graph", func(t *testing.T) {
+		graph := newGraph(t)
+		if err := graph.AddDependency("site", "pop"); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if err := graph.AddDependency("pop", "unknown"); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if err := graph.AddNode("pop", nil); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
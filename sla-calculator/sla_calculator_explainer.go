@@ -21,6 +21,29 @@ const (
 	Sqfbt713NonQuota = 104
 )
 
+// BaktiStatusName returns the readable name of a BAKTI or SQF status.
+func BaktiStatusName(status int) string {
+	switch status {
+	case BaktiRunning:
+		return "running"
+	case BaktiLinkFailure:
+		return "link failure"
+	case BaktiPowerFailure:
+		return "power failure"
+	case BaktiOpen:
+		return "open"
+	case SqfGte71:
+		return "sqf >= 7.1"
+	case Sqflt3:
+		return "sqf < 3"
+	case Sqfbt713Quota:
+		return "sqf 3-7.1 quota"
+	case Sqfbt713NonQuota:
+		return "sqf 3-7.1 non quota"
+	}
+	return ""
+}
+
 // Bakti1UptimeChronology explains the chronolgy of each interval uptime data
 type Bakti1UptimeChronology struct {
 	StartTimestamps     int64
@@ -0,0 +1,145 @@
+package slacalculator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FleetEntry is the availability of a site tagged for fleet aggregation.
+type FleetEntry struct {
+	ID   string
+	Tags map[string]string
+	// Weight is the weight of the site in the weighted average, ie: bandwidth or contract value.
+	Weight       float64
+	Availability float64
+}
+
+// NewFleetEntry returns the fleet entry of a calculator using the formula.
+func NewFleetEntry(id string, tags map[string]string, weight float64, calc *UptimeSLACalculator, formula string) (FleetEntry, error) {
+	result, err := calc.CalculateFormula(formula)
+	if err != nil {
+		return FleetEntry{}, err
+	}
+	return FleetEntry{id, tags, weight, result.Availability}, nil
+}
+
+// NewBakti1FleetEntry returns the fleet entry of a BAKTI availability.
+func NewBakti1FleetEntry(id string, tags map[string]string, weight float64, availability *Bakti1Availability) FleetEntry {
+	return FleetEntry{id, tags, weight, availability.Availability}
+}
+
+// NewBaktiSqfFleetEntry returns the fleet entry of a BAKTI availability considering SQF data.
+func NewBaktiSqfFleetEntry(id string, tags map[string]string, weight float64, availability *BaktiSqfAvailability) FleetEntry {
+	return FleetEntry{id, tags, weight, availability.Availability}
+}
+
+// FleetOptions configures AggregateFleet.
+type FleetOptions struct {
+	// Target is the availability target used to count the sites meeting target.
+	Target float64
+	// GroupBy lists the tag names to group the sites by, ie: province and vendor.
+	GroupBy []string
+	// Unweighted counts every site with weight 1, ignoring the weight of the entries.
+	Unweighted bool
+}
+
+// FleetSummary is the KPI rollup of a group of sites.
+// The WeightedAvailability is DEFAULT_FLOAT_VALUE when the total weight of the group is 0.
+type FleetSummary struct {
+	// Group is the "tag=value" pairs of the group joined by comma, empty for the whole fleet.
+	Group                string
+	Count                int
+	TotalWeight          float64
+	WeightedAvailability float64
+	MeanAvailability     float64
+	MeetingTarget        int
+	MeetingTargetRatio   float64
+	P5                   float64
+	P50                  float64
+	P95                  float64
+}
+
+// FleetReport holds the rollup of the whole fleet and of every group.
+type FleetReport struct {
+	Overall FleetSummary
+	Groups  map[string]FleetSummary
+}
+
+// AggregateFleet rolls the sites up into the fleet and group KPIs.
+func AggregateFleet(entries []FleetEntry, options FleetOptions) (*FleetReport, error) {
+	if len(entries) <= 0 {
+		return nil, fmt.Errorf("no fleet entry to be aggregated")
+	}
+	groups := map[string][]FleetEntry{}
+	for _, entry := range entries {
+		if entry.Availability < 0 || entry.Availability > 1 {
+			return nil, fmt.Errorf("availability of %v should be between 0 and 1: %v", entry.ID, entry.Availability)
+		}
+		if entry.Weight < 0 {
+			return nil, fmt.Errorf("weight of %v should not be less than 0: %v", entry.ID, entry.Weight)
+		}
+		if len(options.GroupBy) > 0 {
+			group := groupOfTags(entry.Tags, options.GroupBy)
+			groups[group] = append(groups[group], entry)
+		}
+	}
+	report := FleetReport{
+		Overall: summarizeFleet("", entries, options),
+		Groups:  map[string]FleetSummary{},
+	}
+	for group, groupEntries := range groups {
+		report.Groups[group] = summarizeFleet(group, groupEntries, options)
+	}
+	return &report, nil
+}
+
+func groupOfTags(tags map[string]string, groupBy []string) string {
+	pairs := []string{}
+	for _, name := range groupBy {
+		pairs = append(pairs, name+"="+tags[name])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func summarizeFleet(group string, entries []FleetEntry, options FleetOptions) FleetSummary {
+	summary := FleetSummary{
+		Group: group,
+		Count: len(entries),
+	}
+	availabilities := []float64{}
+	var weightedSum, sum float64
+	for _, entry := range entries {
+		weight := entry.Weight
+		if options.Unweighted {
+			weight = 1
+		}
+		summary.TotalWeight += weight
+		weightedSum += weight * entry.Availability
+		sum += entry.Availability
+		if entry.Availability >= options.Target {
+			summary.MeetingTarget++
+		}
+		availabilities = append(availabilities, entry.Availability)
+	}
+	sort.Float64s(availabilities)
+	summary.WeightedAvailability = DEFAULT_FLOAT_VALUE
+	if summary.TotalWeight > 0 {
+		summary.WeightedAvailability = weightedSum / summary.TotalWeight
+	}
+	summary.MeanAvailability = sum / float64(len(entries))
+	summary.MeetingTargetRatio = float64(summary.MeetingTarget) / float64(len(entries))
+	summary.P5 = percentile(availabilities, 0.05)
+	summary.P50 = percentile(availabilities, 0.5)
+	summary.P95 = percentile(availabilities, 0.95)
+	return summary
+}
+
+// percentile returns the linear interpolated percentile of the sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,103 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestAggregateFleet(t *testing.T) {
+	entries := []slacalc.FleetEntry{
+		{ID: "site-1", Tags: map[string]string{"province": "papua", "vendor": "a"}, Weight: 2, Availability: 0.99},
+		{ID: "site-2", Tags: map[string]string{"province": "papua", "vendor": "b"}, Weight: 1, Availability: 0.96},
+		{ID: "site-3", Tags: map[string]string{"province": "maluku", "vendor": "a"}, Weight: 1, Availability: 1.0},
+		{ID: "site-4", Tags: map[string]string{"province": "maluku", "vendor": "a"}, Availability: 0.9},
+	}
+	uptimeVals := []int{}
+	timestamps := []int64{}
+	for _, val := range allUpUptimeSeriesData {
+		uptimeVals = append(uptimeVals, val.Value)
+		timestamps = append(timestamps, val.Timestamp)
+	}
+	calc, err := slacalc.NewUptimeSLACalculator(startTime, endTime, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	entry, err := slacalc.NewFleetEntry("site-5", map[string]string{"province": "maluku", "vendor": "b"}, 1, calc, slacalc.FORMULA_SLA1)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	entries = append(entries, entry)
+	report, err := slacalc.AggregateFleet(entries, slacalc.FleetOptions{Target: 0.95, GroupBy: []string{"province"}})
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	overall := report.Overall
+	if overall.Count != 5 || overall.MeetingTarget != 4 {
+		t.Errorf("The overall count is %v with %v meeting target instead of 5 with 4", overall.Count, overall.MeetingTarget)
+	}
+	// The zero weight of site-4 leaves it out of the weighted average
+	if math.Abs(overall.WeightedAvailability-0.988) >= ACCURACY {
+		t.Errorf("The weighted availability is %v instead of 0.988", overall.WeightedAvailability)
+	}
+	if math.Abs(overall.P50-0.99) >= ACCURACY || math.Abs(overall.P5-0.912) >= ACCURACY || math.Abs(overall.P95-1.0) >= ACCURACY {
+		t.Errorf("The percentiles are %v, %v, %v instead of 0.912, 0.99, 1.0", overall.P5, overall.P50, overall.P95)
+	}
+	papua, ok := report.Groups["province=papua"]
+	if !ok {
+		t.Fatalf("No papua group in the report: %v", report.Groups)
+	}
+	if papua.Count != 2 || math.Abs(papua.MeanAvailability-0.975) >= ACCURACY {
+		t.Errorf("The papua group is %+v instead of 2 sites with 0.975 mean", papua)
+	}
+	if len(report.Groups) != 2 {
+		t.Errorf("The amount of groups is %v instead of 2", len(report.Groups))
+	}
+	t.Run("Unweighted", func(t *testing.T) {
+		report, err := slacalc.AggregateFleet(entries, slacalc.FleetOptions{Target: 0.95, Unweighted: true})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if report.Overall.TotalWeight != 5 || math.Abs(report.Overall.WeightedAvailability-0.97) >= ACCURACY {
+			t.Errorf("The unweighted summary is %+v instead of 5 weight with 0.97 availability", report.Overall)
+		}
+	})
+	t.Run("Zero Total Weight", func(t *testing.T) {
+		report, err := slacalc.AggregateFleet(entries[3:4], slacalc.FleetOptions{})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if report.Overall.WeightedAvailability != slacalc.DEFAULT_FLOAT_VALUE {
+			t.Errorf("The weighted availability is %v instead of %v", report.Overall.WeightedAvailability, slacalc.DEFAULT_FLOAT_VALUE)
+		}
+		zeroGroup := []slacalc.FleetEntry{
+			{ID: "site-1", Tags: map[string]string{"vendor": "a"}, Weight: 1, Availability: 0.99},
+			{ID: "site-2", Tags: map[string]string{"vendor": "b"}, Availability: 0.96},
+		}
+		report, err = slacalc.AggregateFleet(zeroGroup, slacalc.FleetOptions{GroupBy: []string{"vendor"}})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if avai := report.Groups["vendor=b"].WeightedAvailability; avai != slacalc.DEFAULT_FLOAT_VALUE {
+			t.Errorf("The weighted availability of vendor=b is %v instead of %v", avai, slacalc.DEFAULT_FLOAT_VALUE)
+		}
+		if avai := report.Groups["vendor=b"].MeanAvailability; avai != 0.96 {
+			t.Errorf("The mean availability of vendor=b is %v instead of 0.96", avai)
+		}
+		if avai := report.Groups["vendor=a"].WeightedAvailability; avai != 0.99 {
+			t.Errorf("The weighted availability of vendor=a is %v instead of 0.99", avai)
+		}
+		if avai := report.Overall.WeightedAvailability; avai != 0.99 {
+			t.Errorf("The overall weighted availability is %v instead of 0.99", avai)
+		}
+	})
+	t.Run("Invalid Entry", func(t *testing.T) {
+		if _, err := slacalc.AggregateFleet([]slacalc.FleetEntry{{ID: "x", Availability: slacalc.DEFAULT_FLOAT_VALUE}}, slacalc.FleetOptions{}); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := slacalc.AggregateFleet(nil, slacalc.FleetOptions{}); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package slacalculator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// FORMULA_BAKTI1 is the formula name of CalcBakti1Uptime.
+const FORMULA_BAKTI1 = "bakti1"
+
+// FormulaInterval is the counted uptime of a time range. The counted uptime is treated
+// as the last seconds of the range, as an uptime counter tells how long the device has been up.
+type FormulaInterval struct {
+	StartTime int64
+	EndTime   int64
+	Counted   int64
+}
+
+// upStart returns the time on which the counted uptime of the interval starts,
+// the counted uptime exceeding the range (tolerated delta glitch) is clipped.
+func (i FormulaInterval) upStart() int64 {
+	if i.Counted > i.EndTime-i.StartTime {
+		return i.StartTime
+	}
+	if i.Counted < 0 {
+		return i.EndTime
+	}
+	return i.EndTime - i.Counted
+}
+
+// FormulaResult is the standard result of a Formula.
+type FormulaResult struct {
+	Formula      string
+	Availability float64
+	Uptime       int64
+	Downtime     int64
+	Intervals    []FormulaInterval
+}
+
+// NewFormulaResult sums the counted intervals of a formula into its result.
+func NewFormulaResult(formula string, intervals []FormulaInterval) *FormulaResult {
+	result := FormulaResult{
+		Formula:      formula,
+		Availability: DEFAULT_FLOAT_VALUE,
+		Intervals:    intervals,
+	}
+	var duration int64
+	for _, interval := range intervals {
+		result.Uptime += interval.Counted
+		duration += interval.EndTime - interval.StartTime
+	}
+	result.Downtime = duration - result.Uptime
+	if duration > 0 {
+		result.Availability = float64(result.Uptime) / float64(duration)
+	}
+	return &result
+}
+
+// Formula calculates the availability of the normalized series held by a calculator.
+type Formula interface {
+	Name() string
+	Calculate(calc *UptimeSLACalculator) (*FormulaResult, error)
+}
+
+type formulaFunc struct {
+	name      string
+	calculate func(calc *UptimeSLACalculator) (*FormulaResult, error)
+}
+
+func (f formulaFunc) Name() string {
+	return f.name
+}
+
+func (f formulaFunc) Calculate(calc *UptimeSLACalculator) (*FormulaResult, error) {
+	return f.calculate(calc)
+}
+
+// NewFormula returns a Formula of the calculate function.
+func NewFormula(name string, calculate func(calc *UptimeSLACalculator) (*FormulaResult, error)) Formula {
+	return formulaFunc{name, calculate}
+}
+
+var (
+	formulaRegistryMutex sync.RWMutex
+	formulaRegistry      = map[string]Formula{}
+)
+
+// RegisterFormula registers the formula so it can be looked up by its name.
+func RegisterFormula(formula Formula) error {
+	if formula == nil || formula.Name() == "" {
+		return fmt.Errorf("formula should have a name")
+	}
+	formulaRegistryMutex.Lock()
+	defer formulaRegistryMutex.Unlock()
+	if _, ok := formulaRegistry[formula.Name()]; ok {
+		return fmt.Errorf("formula %v is already registered", formula.Name())
+	}
+	formulaRegistry[formula.Name()] = formula
+	return nil
+}
+
+// UnregisterFormula removes the registered formula of the name, so the name can be registered again.
+func UnregisterFormula(name string) error {
+	formulaRegistryMutex.Lock()
+	defer formulaRegistryMutex.Unlock()
+	if _, ok := formulaRegistry[name]; !ok {
+		return fmt.Errorf("unknown formula: %v", name)
+	}
+	delete(formulaRegistry, name)
+	return nil
+}
+
+// LookupFormula returns the registered formula of the name.
+func LookupFormula(name string) (Formula, error) {
+	formulaRegistryMutex.RLock()
+	defer formulaRegistryMutex.RUnlock()
+	formula, ok := formulaRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown formula: %v", name)
+	}
+	return formula, nil
+}
+
+// FormulaNames returns the sorted names of the registered formulas.
+func FormulaNames() []string {
+	formulaRegistryMutex.RLock()
+	defer formulaRegistryMutex.RUnlock()
+	names := []string{}
+	for name := range formulaRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CalculateFormula calculates the registered formula of the name.
+func (u *UptimeSLACalculator) CalculateFormula(name string) (*FormulaResult, error) {
+	formula, err := LookupFormula(name)
+	if err != nil {
+		return nil, err
+	}
+	return formula.Calculate(u)
+}
+
+// StartTime returns the start time of the calculator.
+func (u *UptimeSLACalculator) StartTime() int64 {
+	return u.startTime
+}
+
+// EndTime returns the end time of the calculator.
+func (u *UptimeSLACalculator) EndTime() int64 {
+	return u.endTime
+}
+
+// Timestamps returns a copy of the timestamps of the calculator.
+func (u *UptimeSLACalculator) Timestamps() []int64 {
+	return append([]int64{}, u.timestamps...)
+}
+
+// UptimeValues returns a copy of the uptime values of the calculator.
+func (u *UptimeSLACalculator) UptimeValues() []int64 {
+	return append([]int64{}, u.uptimeValues...)
+}
+
+// Exceptions returns a copy of the exceptions of the calculator, nil if it has no exceptions.
+func (u *UptimeSLACalculator) Exceptions() []bool {
+	if u.exceptions == nil {
+		return nil
+	}
+	return append([]bool{}, u.exceptions...)
+}
+
+// ToleranceDeltaRatio returns the tolerance delta ratio of the calculator.
+func (u *UptimeSLACalculator) ToleranceDeltaRatio() float64 {
+	return u.toleranceDeltaRatio
+}
+
+func intervalsOfCountedVals(startTime int64, deltaTimeStamps, countedVals []int64) []FormulaInterval {
+	intervals := []FormulaInterval{}
+	for i := range deltaTimeStamps {
+		intervals = append(intervals, FormulaInterval{
+			StartTime: startTime,
+			EndTime:   startTime + deltaTimeStamps[i],
+			Counted:   countedVals[i],
+		})
+		startTime += deltaTimeStamps[i]
+	}
+	return intervals
+}
+
+func newGenericFormula(name string, countedVals func(u *UptimeSLACalculator) ([]int64, []int64)) Formula {
+	return NewFormula(name, func(calc *UptimeSLACalculator) (*FormulaResult, error) {
+		deltaTimeStamps, counted := countedVals(calc)
+		return NewFormulaResult(name, intervalsOfCountedVals(calc.startTime, deltaTimeStamps, counted)), nil
+	})
+}
+
+func calculateBakti1Formula(calc *UptimeSLACalculator) (*FormulaResult, error) {
+	intervals := []FormulaInterval{}
+	for _, chronology := range calc.ExplainBakti1Uptime() {
+		interval := FormulaInterval{
+			StartTime: chronology.StartTimestamps,
+			EndTime:   chronology.EndTimestamps,
+		}
+		if chronology.Status != BaktiLinkFailure && chronology.Status != BaktiOpen {
+			interval.Counted = interval.EndTime - interval.StartTime
+		}
+		intervals = append(intervals, interval)
+	}
+	return NewFormulaResult(FORMULA_BAKTI1, intervals), nil
+}
+
+func init() {
+	builtins := []Formula{
+		newGenericFormula(FORMULA_SNMP, (*UptimeSLACalculator).snmpCountedVals),
+		newGenericFormula(FORMULA_UPTIME, (*UptimeSLACalculator).uptimeCountedVals),
+		newGenericFormula(FORMULA_SLA1, (*UptimeSLACalculator).sla1CountedVals),
+		NewFormula(FORMULA_SLA2, func(calc *UptimeSLACalculator) (*FormulaResult, error) {
+			if calc.exceptions == nil {
+				return nil, fmt.Errorf("formula %v requires exceptions", FORMULA_SLA2)
+			}
+			deltaTimeStamps, countedVals := calc.sla2CountedVals()
+			return NewFormulaResult(FORMULA_SLA2, intervalsOfCountedVals(calc.startTime, deltaTimeStamps, countedVals)), nil
+		}),
+		NewFormula(FORMULA_BAKTI1, calculateBakti1Formula),
+	}
+	for _, formula := range builtins {
+		if err := RegisterFormula(formula); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package slacalculator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// OUTCOME_UP counts the whole interval as up.
+	OUTCOME_UP = "up"
+	// OUTCOME_DOWN counts the whole interval as down.
+	OUTCOME_DOWN = "down"
+	// OUTCOME_COUNTED counts the spreaded uptime of the interval, as CalculateUptimeAvailability does.
+	OUTCOME_COUNTED = "counted"
+	// OUTCOME_IGNORE lets the exception be classified by the other rules.
+	OUTCOME_IGNORE = "ignore"
+)
+
+// FormulaRules declares the outcome of every interval class. An empty outcome takes
+// the behaviour of CalculateSLA1Availability.
+type FormulaRules struct {
+	// OpenStart is the outcome of the intervals before the first non zero uptime value, default down.
+	OpenStart string `json:"open_start,omitempty" yaml:"open_start,omitempty"`
+	// OpenEnd is the outcome of the intervals after the last non zero uptime value, including
+	// the time between the last timestamp and the end time, default down.
+	OpenEnd string `json:"open_end,omitempty" yaml:"open_end,omitempty"`
+	// Reboot is the outcome of the intervals whose uptime counter is reset, default up.
+	Reboot string `json:"reboot,omitempty" yaml:"reboot,omitempty"`
+	// ZeroValue is the outcome of the zero uptime values while the device is down, default up.
+	ZeroValue string `json:"zero_value,omitempty" yaml:"zero_value,omitempty"`
+	// LinkFailure is the outcome of the zero uptime values while the device is up, default down.
+	LinkFailure string `json:"link_failure,omitempty" yaml:"link_failure,omitempty"`
+	// Running is the outcome of the intervals whose uptime counter increases, default up.
+	Running string `json:"running,omitempty" yaml:"running,omitempty"`
+	// Exception is the outcome of the exception intervals, default ignore.
+	Exception string `json:"exception,omitempty" yaml:"exception,omitempty"`
+	// ToleranceDeltaRatio overrides the tolerance of the calculator when it is set.
+	ToleranceDeltaRatio *float64 `json:"tolerance_delta_ratio,omitempty" yaml:"tolerance_delta_ratio,omitempty"`
+}
+
+// FormulaDefinition declares a formula as data, so it can be loaded from JSON or YAML.
+type FormulaDefinition struct {
+	Name  string       `json:"name" yaml:"name"`
+	Rules FormulaRules `json:"rules" yaml:"rules"`
+}
+
+type formulaDefinitions struct {
+	Formulas []FormulaDefinition `json:"formulas" yaml:"formulas"`
+}
+
+// ParseFormulaDefinitionsJSON parses a JSON document holding a "formulas" list of definitions.
+// Unknown keys are rejected, as ParseFormulaDefinitionsYAML does.
+func ParseFormulaDefinitionsJSON(data []byte) ([]FormulaDefinition, error) {
+	definitions := formulaDefinitions{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&definitions); err != nil {
+		return nil, fmt.Errorf("failed on parsing formula definitions: %v", err)
+	}
+	return definitions.Formulas, nil
+}
+
+// ParseFormulaDefinitionsYAML parses a YAML document holding a "formulas" list of definitions.
+func ParseFormulaDefinitionsYAML(data []byte) ([]FormulaDefinition, error) {
+	definitions := formulaDefinitions{}
+	if err := yaml.UnmarshalStrict(data, &definitions); err != nil {
+		return nil, fmt.Errorf("failed on parsing formula definitions: %v", err)
+	}
+	return definitions.Formulas, nil
+}
+
+// RegisterFormulaDefinitions compiles and registers every definition.
+// Either all of the definitions are registered or none of them, ie: the formulas
+// registered before a failing registration are unregistered again.
+func RegisterFormulaDefinitions(definitions []FormulaDefinition) error {
+	formulas := []Formula{}
+	for _, definition := range definitions {
+		formula, err := definition.Compile()
+		if err != nil {
+			return err
+		}
+		formulas = append(formulas, formula)
+	}
+	for i, formula := range formulas {
+		if err := RegisterFormula(formula); err != nil {
+			for _, registered := range formulas[:i] {
+				UnregisterFormula(registered.Name())
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (r FormulaRules) withDefaults() FormulaRules {
+	defaults := []struct {
+		outcome  *string
+		fallback string
+	}{
+		{&r.OpenStart, OUTCOME_DOWN},
+		{&r.OpenEnd, OUTCOME_DOWN},
+		{&r.Reboot, OUTCOME_UP},
+		{&r.ZeroValue, OUTCOME_UP},
+		{&r.LinkFailure, OUTCOME_DOWN},
+		{&r.Running, OUTCOME_UP},
+		{&r.Exception, OUTCOME_IGNORE},
+	}
+	for _, d := range defaults {
+		if *d.outcome == "" {
+			*d.outcome = d.fallback
+		}
+	}
+	return r
+}
+
+func (r FormulaRules) validate() error {
+	rules := map[string]string{
+		"open_start":   r.OpenStart,
+		"open_end":     r.OpenEnd,
+		"reboot":       r.Reboot,
+		"zero_value":   r.ZeroValue,
+		"link_failure": r.LinkFailure,
+		"running":      r.Running,
+	}
+	for name, outcome := range rules {
+		if outcome != OUTCOME_UP && outcome != OUTCOME_DOWN && outcome != OUTCOME_COUNTED {
+			return fmt.Errorf("unknown outcome of %v rule: %v", name, outcome)
+		}
+	}
+	if r.Exception != OUTCOME_UP && r.Exception != OUTCOME_DOWN && r.Exception != OUTCOME_COUNTED && r.Exception != OUTCOME_IGNORE {
+		return fmt.Errorf("unknown outcome of exception rule: %v", r.Exception)
+	}
+	if r.ToleranceDeltaRatio != nil && (*r.ToleranceDeltaRatio < 0 || *r.ToleranceDeltaRatio > 1) {
+		return fmt.Errorf("tolerance ratio value should be setted between 0 to 1: %v", *r.ToleranceDeltaRatio)
+	}
+	return nil
+}
+
+// Compile validates the definition and returns its Formula.
+func (d FormulaDefinition) Compile() (Formula, error) {
+	if d.Name == "" {
+		return nil, fmt.Errorf("formula definition should have a name")
+	}
+	rules := d.Rules.withDefaults()
+	if err := rules.validate(); err != nil {
+		return nil, fmt.Errorf("invalid formula definition %v: %v", d.Name, err)
+	}
+	return NewFormula(d.Name, func(calc *UptimeSLACalculator) (*FormulaResult, error) {
+		deltaTimeStamps, countedVals := rules.countedVals(calc)
+		return NewFormulaResult(d.Name, intervalsOfCountedVals(calc.startTime, deltaTimeStamps, countedVals)), nil
+	}), nil
+}
+
+func outcomeOf(outcome string, delta, counted int64) int64 {
+	switch outcome {
+	case OUTCOME_UP:
+		return delta
+	case OUTCOME_COUNTED:
+		return counted
+	}
+	return 0
+}
+
+// countedVals classifies every interval the way CalculateSLA1Availability and
+// CalculateSLA2Availability do, then applies the outcome of its class.
+func (r FormulaRules) countedVals(u *UptimeSLACalculator) ([]int64, []int64) {
+	timestamps := u.timestamps
+	uptimeValues := u.uptimeValues
+	toleranceDeltaRatio := u.toleranceDeltaRatio
+	if r.ToleranceDeltaRatio != nil {
+		toleranceDeltaRatio = *r.ToleranceDeltaRatio
+	}
+	deltaTimeStamps, spreadedVals := transformToSpreadedUptime(u.startTime, u.endTime, timestamps, uptimeValues, toleranceDeltaRatio)
+	isException := func(i int) bool {
+		return r.Exception != OUTCOME_IGNORE && u.exceptions != nil && u.exceptions[i]
+	}
+	firstUp, lastUp := len(timestamps), -1
+	for i := range timestamps {
+		if uptimeValues[i] > 0 && !isException(i) && firstUp == len(timestamps) {
+			firstUp = i
+		}
+		if uptimeValues[i] > 0 {
+			lastUp = i
+		}
+	}
+	countedVals := []int64{}
+	for i := range timestamps {
+		var outcome string
+		switch {
+		case isException(i):
+			outcome = r.Exception
+		case i < firstUp:
+			outcome = r.OpenStart
+		case i > lastUp:
+			outcome = r.OpenEnd
+		case uptimeValues[i] <= 0 && spreadedVals[i] > 0:
+			outcome = r.LinkFailure
+		case uptimeValues[i] <= 0:
+			outcome = r.ZeroValue
+		case i > 0 && uptimeValues[i-1] > 0 && uptimeValues[i] <= uptimeValues[i-1]:
+			outcome = r.Reboot
+		default:
+			outcome = r.Running
+		}
+		countedVals = append(countedVals, outcomeOf(outcome, deltaTimeStamps[i], spreadedVals[i]))
+	}
+	if delta := u.endTime - timestamps[len(timestamps)-1]; delta > 0 {
+		deltaTimeStamps = append(deltaTimeStamps, delta)
+		countedVals = append(countedVals, outcomeOf(r.OpenEnd, delta, 0))
+	}
+	return deltaTimeStamps, countedVals
+}
@@ -0,0 +1,130 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+const formulaDefinitionsYAML = `
+formulas:
+  - name: declarative-sla1
+  - name: declarative-sla2
+    rules:
+      exception: up
+  - name: declarative-uptime
+    rules:
+      open_start: counted
+      open_end: counted
+      reboot: counted
+      zero_value: counted
+      link_failure: counted
+      running: counted
+`
+
+const formulaDefinitionsJSON = `{
+	"formulas": [
+		{"name": "declarative-lenient", "rules": {"open_start": "up", "open_end": "up", "tolerance_delta_ratio": 0.5}}
+	]
+}`
+
+func TestFormulaDefinition(t *testing.T) {
+	definitions, err := slacalc.ParseFormulaDefinitionsYAML([]byte(formulaDefinitionsYAML))
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	jsonDefinitions, err := slacalc.ParseFormulaDefinitionsJSON([]byte(formulaDefinitionsJSON))
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	definitions = append(definitions, jsonDefinitions...)
+	if len(definitions) != 4 {
+		t.Fatalf("The amount of definitions is %v instead of 4", len(definitions))
+	}
+	if err := slacalc.RegisterFormulaDefinitions(definitions); err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	defer func() {
+		for _, definition := range definitions {
+			slacalc.UnregisterFormula(definition.Name)
+		}
+	}()
+	for name, seriesData := range map[string][]UptimeData{
+		"Uptime Data": uptimeSeriesData,
+		"All Down":    allDownUptimeSeriesData,
+		"All Up":      allUpUptimeSeriesData,
+		"Bakti Data":  uptimeBaktiSeriesData,
+	} {
+		uptimeVals := []int{}
+		timestamps := []int64{}
+		exceptions := []bool{}
+		for _, val := range seriesData {
+			uptimeVals = append(uptimeVals, val.Value)
+			timestamps = append(timestamps, val.Timestamp)
+			exceptions = append(exceptions, val.Exception)
+		}
+		exceptions[len(exceptions)-1] = true
+		calc, err := slacalc.NewUptimeSLACalculator(startTime, endTime+100, timestamps, uptimeVals, toleranceDeltaRatio, exceptions)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		expected := map[string]float64{
+			"declarative-sla1":   calc.CalculateSLA1Availability(),
+			"declarative-sla2":   calc.CalculateSLA2Availability(),
+			"declarative-uptime": calc.CalculateUptimeAvailability(),
+		}
+		for formula, expectedAvai := range expected {
+			result, err := calc.CalculateFormula(formula)
+			if err != nil {
+				t.Fatalf("An Error should not be accoured: %v", err)
+			}
+			if math.Abs(result.Availability-expectedAvai) >= ACCURACY {
+				t.Errorf("%v: the %v availability is %v instead of %v", name, formula, result.Availability, expectedAvai)
+			}
+		}
+	}
+	t.Run("Lenient Open", func(t *testing.T) {
+		calc, err := slacalc.NewUptimeSLACalculator(0, 500, []int64{100, 200, 300, 400}, []int{0, 100, 200, 0}, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		result, err := calc.CalculateFormula("declarative-lenient")
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if result.Availability != 1 {
+			t.Errorf("The availability is %v instead of 1", result.Availability)
+		}
+	})
+	t.Run("Partial Failure", func(t *testing.T) {
+		valid := slacalc.FormulaDefinition{Name: "declarative-partial"}
+		invalid := slacalc.FormulaDefinition{Name: "declarative-invalid", Rules: slacalc.FormulaRules{Reboot: "maybe"}}
+		duplicate := slacalc.FormulaDefinition{Name: "declarative-sla1"}
+		for _, second := range []slacalc.FormulaDefinition{invalid, duplicate} {
+			if err := slacalc.RegisterFormulaDefinitions([]slacalc.FormulaDefinition{valid, second}); err == nil {
+				t.Errorf("Error should be occured.")
+			}
+			if _, err := slacalc.LookupFormula(valid.Name); err == nil {
+				t.Errorf("The formula %v should not be registered", valid.Name)
+			}
+		}
+		if _, err := slacalc.LookupFormula(duplicate.Name); err != nil {
+			t.Errorf("The formula %v should stay registered: %v", duplicate.Name, err)
+		}
+	})
+	t.Run("Invalid Definition", func(t *testing.T) {
+		if _, err := (slacalc.FormulaDefinition{Name: "invalid", Rules: slacalc.FormulaRules{Reboot: "maybe"}}).Compile(); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := (slacalc.FormulaDefinition{}).Compile(); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := slacalc.ParseFormulaDefinitionsYAML([]byte("formulas:\n  - name: x\n    unknown: y\n")); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := slacalc.ParseFormulaDefinitionsJSON([]byte(`{"formulas": [{"name": "x", "rules": {"open_strat": "up"}}]}`)); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestFormulaRegistry(t *testing.T) {
+	endTime := endTime + 100
+	uptimeVals := []int{}
+	timestamps := []int64{}
+	exceptions := []bool{}
+	for _, val := range uptimeSeriesData {
+		uptimeVals = append(uptimeVals, val.Value)
+		timestamps = append(timestamps, val.Timestamp)
+		exceptions = append(exceptions, val.Exception)
+	}
+	calc, err := slacalc.NewUptimeSLACalculator(startTime, endTime, timestamps, uptimeVals, toleranceDeltaRatio, exceptions)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	t.Run("Built-in Formulas", func(t *testing.T) {
+		expected := map[string]float64{
+			slacalc.FORMULA_SNMP:   calc.CalculateSNMPAvailability(),
+			slacalc.FORMULA_UPTIME: calc.CalculateUptimeAvailability(),
+			slacalc.FORMULA_SLA1:   calc.CalculateSLA1Availability(),
+			slacalc.FORMULA_SLA2:   calc.CalculateSLA2Availability(),
+			slacalc.FORMULA_BAKTI1: calc.CalcBakti1Uptime().Availability,
+		}
+		for formula, expectedAvai := range expected {
+			result, err := calc.CalculateFormula(formula)
+			if err != nil {
+				t.Fatalf("An Error should not be accoured: %v", err)
+			}
+			if math.Abs(result.Availability-expectedAvai) >= ACCURACY {
+				t.Errorf("The %v availability is %v instead of %v", formula, result.Availability, expectedAvai)
+			}
+			if result.Uptime+result.Downtime != endTime-startTime {
+				t.Errorf("The %v uptime and downtime do not cover the period", formula)
+			}
+		}
+	})
+	t.Run("Custom Formula", func(t *testing.T) {
+		alwaysUp := slacalc.NewFormula("always-up", func(calc *slacalc.UptimeSLACalculator) (*slacalc.FormulaResult, error) {
+			return slacalc.NewFormulaResult("always-up", []slacalc.FormulaInterval{
+				{StartTime: calc.StartTime(), EndTime: calc.EndTime(), Counted: calc.EndTime() - calc.StartTime()},
+			}), nil
+		})
+		if err := slacalc.RegisterFormula(alwaysUp); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		defer slacalc.UnregisterFormula("always-up")
+		if err := slacalc.RegisterFormula(alwaysUp); err == nil {
+			t.Errorf("Registering the same formula twice should be failed")
+		}
+		found := false
+		for _, name := range slacalc.FormulaNames() {
+			found = found || name == "always-up"
+		}
+		if !found {
+			t.Errorf("The custom formula is not listed")
+		}
+		rolling, err := calc.CalculateRollingAvailability(1000, 1000, "always-up")
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if rolling["always-up"][0].Availability != 1 {
+			t.Errorf("The custom formula availability is %v instead of 1", rolling["always-up"][0].Availability)
+		}
+	})
+	t.Run("Unknown Formula", func(t *testing.T) {
+		if _, err := slacalc.LookupFormula("unknown"); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if err := slacalc.RegisterFormula(slacalc.NewFormula("", nil)); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if err := slacalc.UnregisterFormula("unknown"); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package slacalculator
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// DEFAULT_HTML_REPORT_TEMPLATE is the default template of WriteHTML. A customer template is executed
+// with the *SLAReport and the functions of HTMLReportFuncs.
+const DEFAULT_HTML_REPORT_TEMPLATE = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 12px; margin: 24px; }
+h1 { font-size: 20px; }
+table { border-collapse: collapse; margin-bottom: 16px; }
+th, td { border: 1px solid #bdbdbd; padding: 4px 8px; text-align: left; }
+th { background: #eeeeee; }
+.timeline { position: relative; width: 100%; height: 24px; border: 1px solid #616161; margin-bottom: 8px; }
+.timeline div { position: absolute; top: 0; height: 100%; }
+.legend span { display: inline-block; margin-right: 12px; }
+.legend i { display: inline-block; width: 12px; height: 12px; margin-right: 4px; vertical-align: middle; }
+.state-up { background: #2e7d32; }
+.state-down { background: #c62828; }
+.state-open { background: #9e9e9e; }
+.state-link-failure { background: #ef6c00; }
+.state-power-failure { background: #6a1b9a; }
+.state-excluded { background: #1565c0; }
+.not-compliant { color: #c62828; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<tr><th>Device</th><td>{{.Device}}</td></tr>
+{{- if .Contract}}
+<tr><th>Contract</th><td>{{.Contract}}</td></tr>
+{{- end}}
+<tr><th>Period</th><td>{{.FormatTime .PeriodStart}} - {{.FormatTime .PeriodEnd}}</td></tr>
+<tr><th>Target</th><td>{{percent .Target}}</td></tr>
+</table>
+<h2>Availability Summary</h2>
+<table>
+<tr><th>Formula</th><th>Availability</th><th>Target</th><th>Compliance</th><th>Uptime</th><th>Downtime</th><th>Penalty Tier</th><th>Penalty Amount</th></tr>
+{{- range .Lines}}
+<tr>
+<td>{{.Formula}}</td>
+<td>{{percent .Availability}}</td>
+<td>{{percent .Target}}</td>
+{{- if .Compliant}}
+<td>Compliant</td>
+{{- else}}
+<td class="not-compliant">Not Compliant</td>
+{{- end}}
+<td>{{duration .Uptime}}</td>
+<td>{{duration .Downtime}}</td>
+{{- if .Credit}}
+<td>{{.Credit.Tier}}</td>
+<td>{{printf "%.2f" .Credit.Amount}}</td>
+{{- else}}
+<td></td>
+<td></td>
+{{- end}}
+</tr>
+{{- end}}
+</table>
+<h2>Timeline</h2>
+<div class="timeline">
+{{- range .Segments}}
+<div class="state-{{.State}}" style="left: {{width ($.TimelineOffset .)}}%; width: {{width ($.TimelineRatio .)}}%" title="{{.State}} {{$.FormatTime .StartTime}} - {{$.FormatTime .EndTime}}"></div>
+{{- end}}
+</div>
+<div class="legend">
+{{- range states}}
+<span><i class="state-{{.}}"></i>{{.}}</span>
+{{- end}}
+</div>
+<h2>Outages</h2>
+{{- with .Outages}}
+<table>
+<tr><th>Start</th><th>End</th><th>Duration</th><th>State</th></tr>
+{{- range .}}
+<tr><td>{{$.FormatTime .StartTime}}</td><td>{{$.FormatTime .EndTime}}</td><td>{{duration .Duration}}</td><td>{{.State}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No outage.</p>
+{{- end}}
+</body>
+</html>
+`
+
+// reportStates lists the timeline states in the legend order.
+var reportStates = []string{
+	REPORT_STATE_UP,
+	REPORT_STATE_DOWN,
+	REPORT_STATE_OPEN,
+	REPORT_STATE_LINK_FAILURE,
+	REPORT_STATE_POWER_FAILURE,
+	REPORT_STATE_EXCLUDED,
+}
+
+// formatReportPercent formats the ratio as a percentage with up to three decimals, ie: 99.955%.
+func formatReportPercent(ratio float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.3f", ratio*100), "0"), ".") + "%"
+}
+
+// HTMLReportFuncs returns the functions available to a report template: percent formats a ratio,
+// duration formats seconds as h:mm:ss, width formats a ratio as a CSS percentage and states lists
+// the timeline states.
+func HTMLReportFuncs() template.FuncMap {
+	return template.FuncMap{
+		"percent":  formatReportPercent,
+		"duration": FormatReportDuration,
+		"width": func(ratio float64) string {
+			return fmt.Sprintf("%.4f", ratio*100)
+		},
+		"states": func() []string {
+			return reportStates
+		},
+	}
+}
+
+// ParseHTMLReportTemplate parses a customer report template along with the functions of HTMLReportFuncs.
+func ParseHTMLReportTemplate(text string) (*template.Template, error) {
+	return template.New("report").Funcs(HTMLReportFuncs()).Parse(text)
+}
+
+var defaultHTMLReportTemplate = template.Must(ParseHTMLReportTemplate(DEFAULT_HTML_REPORT_TEMPLATE))
+
+// WriteHTML writes the report as a self-contained HTML page, nil template means the default template.
+func (r *SLAReport) WriteHTML(w io.Writer, tmpl *template.Template) error {
+	if tmpl == nil {
+		tmpl = defaultHTMLReportTemplate
+	}
+	if err := tmpl.Execute(w, r); err != nil {
+		return fmt.Errorf("failed on executing report template: %v", err)
+	}
+	return nil
+}
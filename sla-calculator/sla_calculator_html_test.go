@@ -0,0 +1,99 @@
+package slacalculator_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestWriteHTML(t *testing.T) {
+	report := newBaktiSLAReport(t)
+	report.Contract = "Contract <A>"
+	report.AddExclusions(slacalc.Exclusion{Category: "maintenance", StartTime: 12900, EndTime: 13100})
+	t.Run("Default Template", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		if err := report.WriteHTML(&buf, nil); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		output := buf.String()
+		expectedParts := []string{
+			"<title>Monthly SLA Report</title>",
+			"<td>site &lt;1&gt;</td>",
+			"<td>Contract &lt;A&gt;</td>",
+			"<td>1970-01-01 02:46:40 - 1970-01-01 03:36:40</td>",
+			"<td>73.333%</td>",
+			`<td class="not-compliant">Not Compliant</td>`,
+			`<div class="state-power-failure" style="left: 6.6667%; width: 3.3333%"`,
+			`<div class="state-excluded" style="left: 96.6667%; width: 3.3333%"`,
+			"<td>1970-01-01 03:23:20</td><td>1970-01-01 03:28:20</td><td>0:05:00</td><td>power-failure</td>",
+		}
+		for _, part := range expectedParts {
+			if !strings.Contains(output, part) {
+				t.Errorf("The report should contain %q", part)
+			}
+		}
+		// The excluded part of the trailing open interval is not an outage
+		if !strings.Contains(output, "<td>1970-01-01 03:33:20</td><td>1970-01-01 03:35:00</td><td>0:01:40</td><td>open</td>") {
+			t.Errorf("The trailing open outage should be shortened by the exclusion")
+		}
+	})
+	t.Run("Customer Template", func(t *testing.T) {
+		tmpl, err := slacalc.ParseHTMLReportTemplate(`<h1>{{.Device}}</h1>{{range .Lines}}<p>{{.Formula}} {{percent .Availability}} {{duration .Downtime}}</p>{{end}}`)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		buf := bytes.Buffer{}
+		if err := report.WriteHTML(&buf, tmpl); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		expected := "<h1>site &lt;1&gt;</h1><p>snmp 60% 0:20:00</p><p>bakti1 73.333% 0:13:20</p>"
+		if buf.String() != expected {
+			t.Errorf("The report is %v instead of %v", buf.String(), expected)
+		}
+	})
+	t.Run("Timeline Gap", func(t *testing.T) {
+		report, err := slacalc.NewSLAReport(slacalc.SLAReportOptions{PeriodStart: 100, PeriodEnd: 500})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		report.Timeline = []slacalc.SLAReportSegment{
+			{StartTime: 200, EndTime: 300, State: slacalc.REPORT_STATE_UP},
+			{StartTime: 400, EndTime: 500, State: slacalc.REPORT_STATE_DOWN},
+		}
+		buf := bytes.Buffer{}
+		if err := report.WriteHTML(&buf, nil); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		for _, part := range []string{
+			`<div class="state-up" style="left: 25.0000%; width: 25.0000%"`,
+			`<div class="state-down" style="left: 75.0000%; width: 25.0000%"`,
+		} {
+			if !strings.Contains(buf.String(), part) {
+				t.Errorf("The report should contain %q", part)
+			}
+		}
+	})
+	t.Run("State Intervals", func(t *testing.T) {
+		report, err := slacalc.NewSLAReport(slacalc.SLAReportOptions{PeriodStart: 100, PeriodEnd: 400})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		report.AddStateIntervals([]slacalc.StateInterval{
+			{StartTime: 100, EndTime: 200, State: slacalc.STATE_UP},
+			{StartTime: 200, EndTime: 300, State: slacalc.STATE_DOWN},
+			{StartTime: 300, EndTime: 400, State: slacalc.STATE_UP},
+		})
+		outages := report.Outages()
+		if len(outages) != 1 || outages[0].State != slacalc.REPORT_STATE_DOWN || outages[0].Duration() != 100 {
+			t.Errorf("The outages are %+v", outages)
+		}
+	})
+}
+
+func TestFormatReportDuration(t *testing.T) {
+	if duration := slacalc.FormatReportDuration(90061); duration != "25:01:01" {
+		t.Errorf("The duration is %v instead of 25:01:01", duration)
+	}
+}
@@ -0,0 +1,336 @@
+package slacalculator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// INFLUX_PRECISION_NS is the nanosecond timestamp precision, the line protocol default.
+	INFLUX_PRECISION_NS = "ns"
+	// INFLUX_PRECISION_US is the microsecond timestamp precision.
+	INFLUX_PRECISION_US = "us"
+	// INFLUX_PRECISION_MS is the millisecond timestamp precision.
+	INFLUX_PRECISION_MS = "ms"
+	// INFLUX_PRECISION_S is the second timestamp precision.
+	INFLUX_PRECISION_S = "s"
+)
+
+// InfluxOptions configures the line protocol reader.
+type InfluxOptions struct {
+	// Measurement filters the points by measurement, empty means every measurement.
+	Measurement string
+	// UptimeField is the field of the uptime, empty means "uptime".
+	UptimeField string
+	// ExceptionField is the optional boolean field of the exception flag, empty means the series
+	// have no exceptions.
+	ExceptionField string
+	// KeyTags lists the tags forming the series key, empty means every tag.
+	KeyTags []string
+	// Precision is the timestamp precision, empty means INFLUX_PRECISION_NS.
+	Precision string
+	// UptimeScale multiplies the uptime values, ie: 0.01 for timeticks, zero means 1.
+	UptimeScale float64
+}
+
+// InfluxWriteOptions configures the line protocol writer.
+type InfluxWriteOptions struct {
+	// Measurement is the measurement of the points, empty means "sla".
+	Measurement string
+	// Tags are added to every point besides the device and formula tags, ie: site.
+	Tags map[string]string
+	// Precision is the timestamp precision, empty means INFLUX_PRECISION_NS.
+	Precision string
+	// Timestamp is the time of the points in unix seconds, ie: the end of the period. Zero means
+	// the points are written without timestamp, so the server time is used.
+	Timestamp int64
+}
+
+type influxPoint struct {
+	measurement string
+	tags        [][2]string
+	fields      map[string]string
+	timestamp   string
+}
+
+func influxPrecisionDivisor(precision string) (int64, error) {
+	switch precision {
+	case "", INFLUX_PRECISION_NS:
+		return 1000000000, nil
+	case INFLUX_PRECISION_US:
+		return 1000000, nil
+	case INFLUX_PRECISION_MS:
+		return 1000, nil
+	case INFLUX_PRECISION_S:
+		return 1, nil
+	}
+	return 0, fmt.Errorf("unknown influx precision: %v", precision)
+}
+
+// splitInfluxLine splits the text on the separator which is neither escaped nor quoted.
+func splitInfluxLine(text string, separator byte, limit int, quoted bool) []string {
+	parts := []string{}
+	start := 0
+	inQuote := false
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			i++
+		case quoted && text[i] == '"':
+			inQuote = !inQuote
+		case text[i] == separator && !inQuote && (limit <= 0 || len(parts) < limit-1):
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, text[start:])
+}
+
+func unescapeInflux(text, escaped string) string {
+	if !strings.Contains(text, `\`) {
+		return text
+	}
+	buf := strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) && strings.IndexByte(escaped, text[i+1]) >= 0 {
+			i++
+		}
+		buf.WriteByte(text[i])
+	}
+	return buf.String()
+}
+
+func parseInfluxLine(line string) (influxPoint, error) {
+	point := influxPoint{fields: map[string]string{}}
+	sections := splitInfluxLine(line, ' ', 3, true)
+	if len(sections) < 2 {
+		return point, fmt.Errorf("missing field set")
+	}
+	series := splitInfluxLine(sections[0], ',', 0, false)
+	point.measurement = unescapeInflux(series[0], `, \`)
+	if point.measurement == "" {
+		return point, fmt.Errorf("missing measurement")
+	}
+	for _, tag := range series[1:] {
+		pair := splitInfluxLine(tag, '=', 2, false)
+		if len(pair) != 2 {
+			return point, fmt.Errorf("invalid tag: %v", tag)
+		}
+		point.tags = append(point.tags, [2]string{unescapeInflux(pair[0], `,= \`), unescapeInflux(pair[1], `,= \`)})
+	}
+	for _, field := range splitInfluxLine(sections[1], ',', 0, true) {
+		pair := splitInfluxLine(field, '=', 2, true)
+		if len(pair) != 2 {
+			return point, fmt.Errorf("invalid field: %v", field)
+		}
+		point.fields[unescapeInflux(pair[0], `,= \`)] = pair[1]
+	}
+	if len(sections) == 3 {
+		point.timestamp = strings.TrimSpace(sections[2])
+	}
+	return point, nil
+}
+
+func parseInfluxNumber(value string) (float64, error) {
+	switch {
+	case strings.HasSuffix(value, "i"):
+		number, err := strconv.ParseInt(strings.TrimSuffix(value, "i"), 10, 64)
+		return float64(number), err
+	case strings.HasSuffix(value, "u"):
+		number, err := strconv.ParseUint(strings.TrimSuffix(value, "u"), 10, 64)
+		return float64(number), err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func parseInfluxBool(value string) (bool, error) {
+	switch value {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	}
+	number, err := parseInfluxNumber(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean: %v", value)
+	}
+	return number != 0, nil
+}
+
+func influxSeriesKey(point influxPoint, keyTags []string) string {
+	tags := [][2]string{}
+	if len(keyTags) <= 0 {
+		tags = append(tags, point.tags...)
+	} else {
+		for _, key := range keyTags {
+			for _, tag := range point.tags {
+				if tag[0] == key {
+					tags = append(tags, tag)
+				}
+			}
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i][0] < tags[j][0]
+	})
+	buf := strings.Builder{}
+	buf.WriteString(escapeInflux(point.measurement, `, `))
+	for _, tag := range tags {
+		buf.WriteString("," + escapeInflux(tag[0], `,= `) + "=" + escapeInflux(tag[1], `,= `))
+	}
+	return buf.String()
+}
+
+// ReadInfluxLineProtocol reads the line protocol into series keyed by the series key, ie:
+// uptime,host=r1, made of the measurement and the key tags sorted by name. Points without the
+// uptime field are skipped, while points without timestamp are rejected, as the server time
+// of the write is unknown.
+func ReadInfluxLineProtocol(r io.Reader, options InfluxOptions) (map[string]UptimeSeries, error) {
+	divisor, err := influxPrecisionDivisor(options.Precision)
+	if err != nil {
+		return nil, err
+	}
+	uptimeField := options.UptimeField
+	if uptimeField == "" {
+		uptimeField = "uptime"
+	}
+	scale := options.UptimeScale
+	if scale == 0 {
+		scale = 1
+	}
+	seriesMap := map[string]UptimeSeries{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		point, err := parseInfluxLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed on parsing line %v: %v", line, err)
+		}
+		if options.Measurement != "" && point.measurement != options.Measurement {
+			continue
+		}
+		value, ok := point.fields[uptimeField]
+		if !ok {
+			continue
+		}
+		if point.timestamp == "" {
+			return nil, fmt.Errorf("line %v has no timestamp", line)
+		}
+		timestamp, err := strconv.ParseInt(point.timestamp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp of line %v: %v", line, point.timestamp)
+		}
+		uptime, err := parseInfluxNumber(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid uptime of line %v: %v", line, value)
+		}
+		exception := false
+		if value, ok := point.fields[options.ExceptionField]; ok && options.ExceptionField != "" {
+			if exception, err = parseInfluxBool(value); err != nil {
+				return nil, fmt.Errorf("invalid exception of line %v: %v", line, err)
+			}
+		}
+		key := influxSeriesKey(point, options.KeyTags)
+		series := seriesMap[key]
+		// Floor division keeps pre-epoch timestamps in the right second
+		seconds := timestamp / divisor
+		if timestamp%divisor < 0 {
+			seconds--
+		}
+		series.Timestamps = append(series.Timestamps, seconds)
+		series.UptimeValues = append(series.UptimeValues, int(math.Round(uptime*scale)))
+		if options.ExceptionField != "" {
+			series.Exceptions = append(series.Exceptions, exception)
+		}
+		seriesMap[key] = series
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed on reading line protocol: %v", err)
+	}
+	return seriesMap, nil
+}
+
+func escapeInflux(text, escaped string) string {
+	if !strings.ContainsAny(text, escaped) {
+		return text
+	}
+	buf := strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		if strings.IndexByte(escaped, text[i]) >= 0 {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(text[i])
+	}
+	return buf.String()
+}
+
+// WriteFormulaResultsLineProtocol writes the formula results keyed by device and formula as line
+// protocol points, sorted by both, with device and formula tags and availability, uptime and
+// downtime fields.
+func WriteFormulaResultsLineProtocol(w io.Writer, results map[string]map[string]*FormulaResult, options InfluxWriteOptions) error {
+	multiplier, err := influxPrecisionDivisor(options.Precision)
+	if err != nil {
+		return err
+	}
+	measurement := options.Measurement
+	if measurement == "" {
+		measurement = "sla"
+	}
+	extraTags := []string{}
+	for key := range options.Tags {
+		if key == "device" || key == "formula" {
+			return fmt.Errorf("tag %v is reserved", key)
+		}
+		extraTags = append(extraTags, key)
+	}
+	devices := []string{}
+	for device := range results {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+	buf := bytes.Buffer{}
+	for _, device := range devices {
+		formulas := []string{}
+		for formula := range results[device] {
+			formulas = append(formulas, formula)
+		}
+		sort.Strings(formulas)
+		for _, formula := range formulas {
+			result := results[device][formula]
+			tags := map[string]string{"device": device, "formula": formula}
+			for _, key := range extraTags {
+				tags[key] = options.Tags[key]
+			}
+			keys := append([]string{"device", "formula"}, extraTags...)
+			sort.Strings(keys)
+			buf.WriteString(escapeInflux(measurement, `, `))
+			for _, key := range keys {
+				// Line protocol does not allow empty tag values
+				if tags[key] == "" {
+					continue
+				}
+				buf.WriteString("," + escapeInflux(key, `,= `) + "=" + escapeInflux(tags[key], `,= `))
+			}
+			fmt.Fprintf(&buf, " availability=%v,uptime=%vi,downtime=%vi",
+				strconv.FormatFloat(result.Availability, 'f', -1, 64), result.Uptime, result.Downtime)
+			if options.Timestamp != 0 {
+				fmt.Fprintf(&buf, " %v", options.Timestamp*multiplier)
+			}
+			buf.WriteString("\n")
+		}
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
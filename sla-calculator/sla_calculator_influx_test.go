@@ -0,0 +1,111 @@
+package slacalculator_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestReadInfluxLineProtocol(t *testing.T) {
+	t.Run("Tag Set Grouping", func(t *testing.T) {
+		file, err := os.Open("testdata/influx_uptime.lp")
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		defer file.Close()
+		seriesMap, err := slacalc.ReadInfluxLineProtocol(file, slacalc.InfluxOptions{ExceptionField: "exception"})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(seriesMap) != 2 {
+			t.Fatalf("The amount of series is %v instead of 2: %v", len(seriesMap), seriesMap)
+		}
+		r1 := seriesMap[`uptime,host=r1,site=jakarta\ pusat`]
+		if len(r1.Timestamps) != 2 || r1.Timestamps[1] != 1700000060 || r1.UptimeValues[1] != 1060 {
+			t.Errorf("The series of r1 is %+v", r1)
+		}
+		r2 := seriesMap["uptime,host=r2,site=bandung"]
+		if len(r2.Timestamps) != 2 || r2.UptimeValues[0] != 5 || !r2.Exceptions[1] {
+			t.Errorf("The series of r2 is %+v", r2)
+		}
+	})
+	t.Run("Key Tags And Precision", func(t *testing.T) {
+		input := "uptime,host=r1,poller=p1 uptime=100i 1700000000000\nuptime,host=r1,poller=p2 uptime=160i 1700000060000\n"
+		seriesMap, err := slacalc.ReadInfluxLineProtocol(strings.NewReader(input), slacalc.InfluxOptions{KeyTags: []string{"host"}, Precision: slacalc.INFLUX_PRECISION_MS})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		series := seriesMap["uptime,host=r1"]
+		if len(series.Timestamps) != 2 || series.Timestamps[0] != 1700000000 || series.Timestamps[1] != 1700000060 {
+			t.Errorf("The series is %+v", series)
+		}
+		if series.Exceptions != nil {
+			t.Errorf("The exceptions should be nil without exception field")
+		}
+	})
+	t.Run("Missing Timestamp", func(t *testing.T) {
+		_, err := slacalc.ReadInfluxLineProtocol(strings.NewReader("uptime,host=r1 uptime=100i\n"), slacalc.InfluxOptions{})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+	t.Run("Unknown Precision", func(t *testing.T) {
+		_, err := slacalc.ReadInfluxLineProtocol(strings.NewReader(""), slacalc.InfluxOptions{Precision: "h"})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
+
+func TestWriteFormulaResultsLineProtocol(t *testing.T) {
+	results := map[string]map[string]*slacalc.FormulaResult{
+		"r 1": {
+			slacalc.FORMULA_SLA1: {Formula: slacalc.FORMULA_SLA1, Availability: 0.75, Uptime: 2250, Downtime: 750},
+			slacalc.FORMULA_SNMP: {Formula: slacalc.FORMULA_SNMP, Availability: 0.5, Uptime: 1500, Downtime: 1500},
+		},
+	}
+	buf := bytes.Buffer{}
+	err := slacalc.WriteFormulaResultsLineProtocol(&buf, results, slacalc.InfluxWriteOptions{
+		Tags:      map[string]string{"site": "jakarta"},
+		Precision: slacalc.INFLUX_PRECISION_S,
+		Timestamp: 1700003000,
+	})
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	expected := `sla,device=r\ 1,formula=sla1,site=jakarta availability=0.75,uptime=2250i,downtime=750i 1700003000` + "\n" +
+		`sla,device=r\ 1,formula=snmp,site=jakarta availability=0.5,uptime=1500i,downtime=1500i 1700003000` + "\n"
+	if buf.String() != expected {
+		t.Errorf("The line protocol is\n%v\ninstead of\n%v", buf.String(), expected)
+	}
+	t.Run("Round Trip", func(t *testing.T) {
+		seriesMap, err := slacalc.ReadInfluxLineProtocol(&buf, slacalc.InfluxOptions{UptimeField: "uptime", KeyTags: []string{"device"}, Precision: slacalc.INFLUX_PRECISION_S})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		series := seriesMap[`sla,device=r\ 1`]
+		if len(series.UptimeValues) != 2 || series.UptimeValues[0] != 2250 {
+			t.Errorf("The series is %+v", series)
+		}
+	})
+	t.Run("Server Timestamp", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		if err := slacalc.WriteFormulaResultsLineProtocol(&buf, results, slacalc.InfluxWriteOptions{}); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		expected := `sla,device=r\ 1,formula=sla1 availability=0.75,uptime=2250i,downtime=750i` + "\n" +
+			`sla,device=r\ 1,formula=snmp availability=0.5,uptime=1500i,downtime=1500i` + "\n"
+		if buf.String() != expected {
+			t.Errorf("The line protocol is\n%v\ninstead of\n%v", buf.String(), expected)
+		}
+	})
+	t.Run("Reserved Tag", func(t *testing.T) {
+		err := slacalc.WriteFormulaResultsLineProtocol(&bytes.Buffer{}, results, slacalc.InfluxWriteOptions{Tags: map[string]string{"device": "x"}})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
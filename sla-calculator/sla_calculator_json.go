@@ -0,0 +1,315 @@
+package slacalculator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// CALCULATION_SCHEMA_VERSION is the version of the JSON calculation request and result format.
+const CALCULATION_SCHEMA_VERSION = "1.0"
+
+const (
+	// DUPLICATE_RULE_MAX is the JSON name of DuplicateKeepMax.
+	DUPLICATE_RULE_MAX = "max"
+	// DUPLICATE_RULE_FIRST is the JSON name of DuplicateKeepFirst.
+	DUPLICATE_RULE_FIRST = "first"
+	// DUPLICATE_RULE_LAST is the JSON name of DuplicateKeepLast.
+	DUPLICATE_RULE_LAST = "last"
+)
+
+// CalculationPeriod is the calculation period in unix seconds.
+type CalculationPeriod struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// CalculationSample is an uptime series data of a calculation request.
+type CalculationSample struct {
+	Timestamp int64 `json:"timestamp"`
+	Uptime    int   `json:"uptime"`
+	Exception bool  `json:"exception,omitempty"`
+}
+
+// CalculationOptions configures how a calculation request is calculated.
+type CalculationOptions struct {
+	ToleranceDeltaRatio float64 `json:"tolerance_delta_ratio"`
+	// DuplicateRule normalizes the series when it is set, ie: max, first or last.
+	DuplicateRule     string `json:"duplicate_rule,omitempty"`
+	CorrectClockDrift bool   `json:"correct_clock_drift,omitempty"`
+	IncludeIntervals  bool   `json:"include_intervals,omitempty"`
+}
+
+// CalculationRequest is the JSON calculation request of a device series.
+type CalculationRequest struct {
+	Version  string              `json:"version"`
+	DeviceID string              `json:"device_id,omitempty"`
+	Period   CalculationPeriod   `json:"period"`
+	Series   []CalculationSample `json:"series"`
+	Formulas []string            `json:"formulas"`
+	Options  CalculationOptions  `json:"options"`
+}
+
+// CalculationInterval is a counted interval of a formula result.
+type CalculationInterval struct {
+	Start   int64 `json:"start"`
+	End     int64 `json:"end"`
+	Counted int64 `json:"counted"`
+}
+
+// CalculationFormulaResult is the JSON result of a formula.
+type CalculationFormulaResult struct {
+	Formula      string                `json:"formula"`
+	Availability float64               `json:"availability"`
+	Uptime       int64                 `json:"uptime"`
+	Downtime     int64                 `json:"downtime"`
+	Intervals    []CalculationInterval `json:"intervals,omitempty"`
+}
+
+// CalculationClockDrift is the JSON clock drift of a calculation result.
+type CalculationClockDrift struct {
+	Rate      float64 `json:"rate"`
+	Drift     float64 `json:"drift"`
+	Intervals int     `json:"intervals"`
+	Corrected bool    `json:"corrected"`
+}
+
+// CalculationNormalization is the JSON normalization report of a calculation result.
+type CalculationNormalization struct {
+	Reordered           int     `json:"reordered"`
+	DuplicatesRemoved   int     `json:"duplicates_removed"`
+	DuplicateTimestamps []int64 `json:"duplicate_timestamps,omitempty"`
+}
+
+// CalculationBaktiChronology is the JSON form of Bakti1UptimeChronology and BaktiSqfChronology.
+type CalculationBaktiChronology struct {
+	Start               int64    `json:"start"`
+	End                 int64    `json:"end"`
+	UptimeValue         int64    `json:"uptime_value"`
+	Status              int      `json:"status"`
+	StatusName          string   `json:"status_name"`
+	LinkFailureDuration int64    `json:"link_failure_duration"`
+	RestitutionDuration int64    `json:"restitution_duration"`
+	SqfStatus           int      `json:"sqf_status,omitempty"`
+	SqfStatusName       string   `json:"sqf_status_name,omitempty"`
+	SqfValue            *float64 `json:"sqf_value,omitempty"`
+	RainQuota           *int64   `json:"rain_quota,omitempty"`
+}
+
+// CalculationBaktiAvailability is the JSON form of Bakti1Availability and BaktiSqfAvailability.
+type CalculationBaktiAvailability struct {
+	Availability        float64                      `json:"availability"`
+	LinkFailureDuration int64                        `json:"link_failure_duration"`
+	RestitutionDuration int64                        `json:"restitution_duration"`
+	OpenDuration        int64                        `json:"open_duration"`
+	RainQuotaUsed       *int64                       `json:"rain_quota_used,omitempty"`
+	Chronologies        []CalculationBaktiChronology `json:"chronologies"`
+}
+
+// CalculationResult is the JSON calculation result of a CalculationRequest.
+type CalculationResult struct {
+	Version             string                        `json:"version"`
+	DeviceID            string                        `json:"device_id,omitempty"`
+	Period              CalculationPeriod             `json:"period"`
+	CalculatedAt        int64                         `json:"calculated_at"`
+	ToleranceDeltaRatio float64                       `json:"tolerance_delta_ratio"`
+	Samples             int                           `json:"samples"`
+	Normalization       *CalculationNormalization     `json:"normalization,omitempty"`
+	ClockDrift          CalculationClockDrift         `json:"clock_drift"`
+	Results             []CalculationFormulaResult    `json:"results"`
+	Bakti1              *CalculationBaktiAvailability `json:"bakti1,omitempty"`
+}
+
+func duplicateRuleOf(name string) (DuplicateRule, error) {
+	switch name {
+	case DUPLICATE_RULE_MAX:
+		return DuplicateKeepMax, nil
+	case DUPLICATE_RULE_FIRST:
+		return DuplicateKeepFirst, nil
+	case DUPLICATE_RULE_LAST:
+		return DuplicateKeepLast, nil
+	}
+	return DuplicateKeepMax, fmt.Errorf("unknown duplicate rule: %v", name)
+}
+
+// DecodeCalculationRequest decodes and checks the version of a JSON calculation request.
+func DecodeCalculationRequest(r io.Reader) (*CalculationRequest, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	request := CalculationRequest{}
+	if err := decoder.Decode(&request); err != nil {
+		return nil, fmt.Errorf("failed on decoding calculation request: %v", err)
+	}
+	if request.Version != CALCULATION_SCHEMA_VERSION {
+		return nil, fmt.Errorf("unsupported calculation request version: %v", request.Version)
+	}
+	return &request, nil
+}
+
+// EncodeCalculationRequest encodes the calculation request as JSON with the current version,
+// the request itself is left unchanged.
+func EncodeCalculationRequest(w io.Writer, request *CalculationRequest) error {
+	versioned := *request
+	versioned.Version = CALCULATION_SCHEMA_VERSION
+	return json.NewEncoder(w).Encode(versioned)
+}
+
+// DecodeCalculationResult decodes and checks the version of a JSON calculation result.
+func DecodeCalculationResult(r io.Reader) (*CalculationResult, error) {
+	result := CalculationResult{}
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed on decoding calculation result: %v", err)
+	}
+	if result.Version != CALCULATION_SCHEMA_VERSION {
+		return nil, fmt.Errorf("unsupported calculation result version: %v", result.Version)
+	}
+	return &result, nil
+}
+
+// EncodeCalculationResult encodes the calculation result as JSON.
+func EncodeCalculationResult(w io.Writer, result *CalculationResult) error {
+	return json.NewEncoder(w).Encode(result)
+}
+
+// UptimeSeries returns the uptime series of the request samples. Its exceptions are nil unless
+// a sample is an exception or SLA2 is requested, so SLA2 is not calculated by default without exceptions.
+func (r *CalculationRequest) UptimeSeries() UptimeSeries {
+	series := UptimeSeries{}
+	exceptions := []bool{}
+	withExceptions := false
+	for _, formula := range r.Formulas {
+		withExceptions = withExceptions || formula == FORMULA_SLA2
+	}
+	for _, sample := range r.Series {
+		series.Timestamps = append(series.Timestamps, sample.Timestamp)
+		series.UptimeValues = append(series.UptimeValues, sample.Uptime)
+		exceptions = append(exceptions, sample.Exception)
+		withExceptions = withExceptions || sample.Exception
+	}
+	if withExceptions {
+		series.Exceptions = exceptions
+	}
+	return series
+}
+
+// Execute calculates the request and returns its result. Every generic formula is calculated
+// when the request has no formula.
+func (r *CalculationRequest) Execute() (*CalculationResult, error) {
+	result := CalculationResult{
+		Version:             CALCULATION_SCHEMA_VERSION,
+		DeviceID:            r.DeviceID,
+		Period:              r.Period,
+		CalculatedAt:        time.Now().Unix(),
+		ToleranceDeltaRatio: r.Options.ToleranceDeltaRatio,
+	}
+	series := r.UptimeSeries()
+	if r.Options.DuplicateRule != "" {
+		rule, err := duplicateRuleOf(r.Options.DuplicateRule)
+		if err != nil {
+			return nil, err
+		}
+		normalized, report, err := NormalizeSeries(series, rule)
+		if err != nil {
+			return nil, err
+		}
+		series = normalized
+		result.Normalization = &CalculationNormalization{
+			Reordered:           report.Reordered,
+			DuplicatesRemoved:   report.DuplicatesRemoved,
+			DuplicateTimestamps: report.DuplicateTimestamps,
+		}
+	}
+	result.Samples = len(series.Timestamps)
+	calc, err := series.NewCalculator(r.Period.Start, r.Period.End, r.Options.ToleranceDeltaRatio)
+	if err != nil {
+		return nil, err
+	}
+	drift := calc.EstimateClockDrift()
+	if r.Options.CorrectClockDrift {
+		calc, drift = calc.CorrectClockDrift()
+	}
+	result.ClockDrift = CalculationClockDrift(drift)
+	formulas := r.Formulas
+	if len(formulas) <= 0 {
+		formulas = calc.availableFormulas()
+	}
+	for _, formula := range formulas {
+		formulaResult, err := calc.CalculateFormula(formula)
+		if err != nil {
+			return nil, err
+		}
+		result.Results = append(result.Results, NewCalculationFormulaResult(formulaResult, r.Options.IncludeIntervals))
+		if formula == FORMULA_BAKTI1 {
+			result.Bakti1 = NewCalculationBakti1Availability(calc.CalcBakti1Uptime())
+		}
+	}
+	return &result, nil
+}
+
+// NewCalculationFormulaResult returns the JSON form of a formula result.
+func NewCalculationFormulaResult(formulaResult *FormulaResult, includeIntervals bool) CalculationFormulaResult {
+	result := CalculationFormulaResult{
+		Formula:      formulaResult.Formula,
+		Availability: formulaResult.Availability,
+		Uptime:       formulaResult.Uptime,
+		Downtime:     formulaResult.Downtime,
+	}
+	if includeIntervals {
+		for _, interval := range formulaResult.Intervals {
+			result.Intervals = append(result.Intervals, CalculationInterval{interval.StartTime, interval.EndTime, interval.Counted})
+		}
+	}
+	return result
+}
+
+func newCalculationBaktiChronology(chronology Bakti1UptimeChronology) CalculationBaktiChronology {
+	return CalculationBaktiChronology{
+		Start:               chronology.StartTimestamps,
+		End:                 chronology.EndTimestamps,
+		UptimeValue:         chronology.UptimeValue,
+		Status:              chronology.Status,
+		StatusName:          BaktiStatusName(chronology.Status),
+		LinkFailureDuration: chronology.LinkFailureDuration,
+		RestitutionDuration: chronology.RestitutionDuration,
+	}
+}
+
+// NewCalculationBakti1Availability returns the JSON form of a BAKTI availability.
+func NewCalculationBakti1Availability(availability *Bakti1Availability) *CalculationBaktiAvailability {
+	result := CalculationBaktiAvailability{
+		Availability:        availability.Availability,
+		LinkFailureDuration: availability.LinkFailureDuration,
+		RestitutionDuration: availability.RestitutionDuration,
+		OpenDuration:        availability.OpenDuration,
+		Chronologies:        []CalculationBaktiChronology{},
+	}
+	for _, chronology := range availability.Chronologies {
+		result.Chronologies = append(result.Chronologies, newCalculationBaktiChronology(chronology))
+	}
+	return &result
+}
+
+// NewCalculationBaktiSqfAvailability returns the JSON form of a BAKTI availability considering SQF data.
+func NewCalculationBaktiSqfAvailability(availability *BaktiSqfAvailability) *CalculationBaktiAvailability {
+	rainQuotaUsed := availability.RainQuotaUsed
+	result := CalculationBaktiAvailability{
+		Availability:        availability.Availability,
+		LinkFailureDuration: availability.LinkFailureDuration,
+		RestitutionDuration: availability.RestitutionDuration,
+		OpenDuration:        availability.OpenDuration,
+		RainQuotaUsed:       &rainQuotaUsed,
+		Chronologies:        []CalculationBaktiChronology{},
+	}
+	for _, chronology := range availability.Chronologies {
+		sqfValue := chronology.SqfValue
+		rainQuota := chronology.RainQuota
+		jsonChronology := newCalculationBaktiChronology(chronology.Bakti1UptimeChronology)
+		jsonChronology.SqfStatus = chronology.SqfStatus
+		jsonChronology.SqfStatusName = BaktiStatusName(chronology.SqfStatus)
+		jsonChronology.SqfValue = &sqfValue
+		jsonChronology.RainQuota = &rainQuota
+		result.Chronologies = append(result.Chronologies, jsonChronology)
+	}
+	return &result
+}
@@ -0,0 +1,213 @@
+package slacalculator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func newCalculationRequest() *slacalc.CalculationRequest {
+	request := slacalc.CalculationRequest{
+		DeviceID: "device-1",
+		Period:   slacalc.CalculationPeriod{Start: startTime, End: endTime + 100},
+		Formulas: []string{slacalc.FORMULA_SNMP, slacalc.FORMULA_UPTIME, slacalc.FORMULA_SLA1, slacalc.FORMULA_BAKTI1},
+		Options:  slacalc.CalculationOptions{ToleranceDeltaRatio: toleranceDeltaRatio, IncludeIntervals: true},
+	}
+	for _, data := range uptimeSeriesData {
+		request.Series = append(request.Series, slacalc.CalculationSample{
+			Timestamp: data.Timestamp,
+			Uptime:    data.Value,
+			Exception: data.Exception,
+		})
+	}
+	return &request
+}
+
+func TestCalculationRequest(t *testing.T) {
+	t.Run("Round Trip", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		if err := slacalc.EncodeCalculationRequest(&buf, newCalculationRequest()); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		request, err := slacalc.DecodeCalculationRequest(&buf)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if request.Version != slacalc.CALCULATION_SCHEMA_VERSION {
+			t.Errorf("The version is %v instead of %v", request.Version, slacalc.CALCULATION_SCHEMA_VERSION)
+		}
+		if len(request.Series) != len(uptimeSeriesData) {
+			t.Errorf("The amount of samples is %v instead of %v", len(request.Series), len(uptimeSeriesData))
+		}
+	})
+	t.Run("Encode Leaves Request", func(t *testing.T) {
+		request := newCalculationRequest()
+		if err := slacalc.EncodeCalculationRequest(&bytes.Buffer{}, request); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if request.Version != "" {
+			t.Errorf("The request version is changed to %v", request.Version)
+		}
+	})
+	t.Run("Stable Field Names", func(t *testing.T) {
+		input := `{"version":"1.0","device_id":"d","period":{"start":10000,"end":13000},` +
+			`"series":[{"timestamp":10100,"uptime":100,"exception":true}],"formulas":["sla1"],` +
+			`"options":{"tolerance_delta_ratio":0.9,"duplicate_rule":"last"}}`
+		request, err := slacalc.DecodeCalculationRequest(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if request.Period.End != 13000 || !request.Series[0].Exception || request.Options.DuplicateRule != "last" {
+			t.Errorf("The request is decoded wrongly: %+v", request)
+		}
+	})
+	t.Run("Unsupported Version", func(t *testing.T) {
+		_, err := slacalc.DecodeCalculationRequest(strings.NewReader(`{"version":"0.1"}`))
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+	t.Run("Unknown Field", func(t *testing.T) {
+		_, err := slacalc.DecodeCalculationRequest(strings.NewReader(`{"version":"1.0","unknown":1}`))
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
+
+func TestCalculationRequestExecute(t *testing.T) {
+	t.Run("Formulas", func(t *testing.T) {
+		result, err := newCalculationRequest().Execute()
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		expectedAvailabilities := []float64{expetedSNMPAvailability, expetedUptimeAvailability, expetedSLA1Availability}
+		for i, expected := range expectedAvailabilities {
+			if math.Abs(result.Results[i].Availability-expected) >= ACCURACY {
+				t.Errorf("The %v availability is %v instead of %v", result.Results[i].Formula, result.Results[i].Availability, expected)
+			}
+			if len(result.Results[i].Intervals) <= 0 {
+				t.Errorf("The %v intervals should be included", result.Results[i].Formula)
+			}
+		}
+		if result.Bakti1 == nil || len(result.Bakti1.Chronologies) <= 0 {
+			t.Fatalf("The bakti1 availability should be included")
+		}
+		if result.Bakti1.Chronologies[0].StatusName == "" {
+			t.Errorf("The status name should not be empty")
+		}
+		if result.ClockDrift.Intervals <= 0 {
+			t.Errorf("The clock drift should be estimated")
+		}
+	})
+	t.Run("Default Formulas", func(t *testing.T) {
+		request := newCalculationRequest()
+		request.Formulas = nil
+		result, err := request.Execute()
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(result.Results) != 4 {
+			t.Errorf("The amount of results is %v instead of 4", len(result.Results))
+		}
+	})
+	t.Run("Without Exception", func(t *testing.T) {
+		request := newCalculationRequest()
+		request.Formulas = nil
+		for i := range request.Series {
+			request.Series[i].Exception = false
+		}
+		if request.UptimeSeries().Exceptions != nil {
+			t.Errorf("The exceptions should be nil")
+		}
+		result, err := request.Execute()
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		for _, formulaResult := range result.Results {
+			if formulaResult.Formula == slacalc.FORMULA_SLA2 {
+				t.Errorf("The sla2 should not be calculated without exception")
+			}
+		}
+	})
+	t.Run("Requested SLA2 Without Exception", func(t *testing.T) {
+		request := newCalculationRequest()
+		request.Formulas = []string{slacalc.FORMULA_SLA1, slacalc.FORMULA_SLA2}
+		for i := range request.Series {
+			request.Series[i].Exception = false
+		}
+		result, err := request.Execute()
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(result.Results) != 2 || result.Results[1].Availability != result.Results[0].Availability {
+			t.Errorf("The sla2 without exception should equal sla1: %+v", result.Results)
+		}
+	})
+	t.Run("Normalization", func(t *testing.T) {
+		request := newCalculationRequest()
+		request.Series = append(request.Series, request.Series[0])
+		request.Options.DuplicateRule = slacalc.DUPLICATE_RULE_MAX
+		result, err := request.Execute()
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if result.Normalization == nil || result.Normalization.DuplicatesRemoved != 1 {
+			t.Errorf("The duplicate should be removed: %+v", result.Normalization)
+		}
+	})
+	t.Run("Unknown Formula", func(t *testing.T) {
+		request := newCalculationRequest()
+		request.Formulas = []string{"unknown"}
+		if _, err := request.Execute(); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+	t.Run("Result Round Trip", func(t *testing.T) {
+		result, err := newCalculationRequest().Execute()
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		buf := bytes.Buffer{}
+		if err := slacalc.EncodeCalculationResult(&buf, result); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		for _, field := range []string{"version", "device_id", "period", "calculated_at", "tolerance_delta_ratio", "clock_drift", "results", "bakti1"} {
+			if _, ok := fields[field]; !ok {
+				t.Errorf("The field %v is missing", field)
+			}
+		}
+		decoded, err := slacalc.DecodeCalculationResult(&buf)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if decoded.DeviceID != result.DeviceID || len(decoded.Results) != len(result.Results) {
+			t.Errorf("The result is decoded wrongly: %+v", decoded)
+		}
+	})
+}
+
+func TestNewCalculationBaktiSqfAvailability(t *testing.T) {
+	availability := &slacalc.BaktiSqfAvailability{
+		Availability:  0.5,
+		RainQuotaUsed: 10,
+		Chronologies: []slacalc.BaktiSqfChronology{
+			{SqfStatus: 4, SqfValue: 7.5, RainQuota: 20},
+		},
+	}
+	result := slacalc.NewCalculationBaktiSqfAvailability(availability)
+	if result.RainQuotaUsed == nil || *result.RainQuotaUsed != 10 {
+		t.Errorf("The rain quota used should be 10")
+	}
+	if result.Chronologies[0].SqfValue == nil || *result.Chronologies[0].SqfValue != 7.5 {
+		t.Errorf("The sqf value should be 7.5")
+	}
+}
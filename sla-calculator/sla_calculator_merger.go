@@ -0,0 +1,162 @@
+package slacalculator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ConsensusRule decides the merged state of a timestamp polled by several pollers.
+type ConsensusRule int
+
+const (
+	// ConsensusAnyUp counts the timestamp as up if any poller sees the device up.
+	ConsensusAnyUp ConsensusRule = iota
+	// ConsensusAllUp counts the timestamp as up only if every poller sees the device up.
+	ConsensusAllUp
+	// ConsensusMajority counts the timestamp as up if more than half of the pollers see the device up.
+	ConsensusMajority
+)
+
+// MergeOptions configures MergeSeries.
+type MergeOptions struct {
+	Rule ConsensusRule
+	// AlignWindow is the maximum distance in seconds between samples of different pollers
+	// to be treated as the same poll. Zero means only the exact same timestamps are aligned.
+	AlignWindow int64
+}
+
+// PollerDisagreement explains a merged timestamp on which the pollers see different states.
+type PollerDisagreement struct {
+	Timestamp int64
+	// UpSources and DownSources are the indexes of the sources which see the device up or down.
+	UpSources   []int
+	DownSources []int
+	// Up is the merged state after applying the consensus rule.
+	Up bool
+}
+
+// MergeReport explains how the sources are merged by MergeSeries.
+type MergeReport struct {
+	// Slots is the amount of merged timestamps.
+	Slots int
+	// Disagreements lists every merged timestamp on which the pollers disagree.
+	Disagreements []PollerDisagreement
+	// Overruled counts, per source, the votes which are different from the merged state.
+	Overruled []int
+}
+
+type pollerSample struct {
+	timestamp int64
+	source    int
+	value     int
+	exception bool
+}
+
+// MergeSeries builds one authoritative series from the series of redundant pollers of the same device.
+// Samples of different pollers within the align window are merged into one timestamp, and its state
+// is decided by the consensus rule. An up timestamp carries the highest uptime value among the up
+// pollers, shifted to the merged timestamp, while a down timestamp carries zero. The merged series
+// has exceptions only when a source has exceptions.
+func MergeSeries(sources []UptimeSeries, options MergeOptions) (UptimeSeries, MergeReport, error) {
+	report := MergeReport{Overruled: make([]int, len(sources))}
+	if len(sources) <= 0 {
+		return UptimeSeries{}, report, fmt.Errorf("no source to be merged")
+	}
+	if options.AlignWindow < 0 {
+		return UptimeSeries{}, report, fmt.Errorf("align window should not be less than 0: %v", options.AlignWindow)
+	}
+	if options.Rule != ConsensusAnyUp && options.Rule != ConsensusAllUp && options.Rule != ConsensusMajority {
+		return UptimeSeries{}, report, fmt.Errorf("unknown consensus rule: %v", options.Rule)
+	}
+	samples := []pollerSample{}
+	hasExceptions := false
+	for i, source := range sources {
+		normalized, _, err := NormalizeSeries(source, DuplicateKeepMax)
+		if err != nil {
+			return UptimeSeries{}, report, fmt.Errorf("failed on normalizing source %v: %v", i, err)
+		}
+		for j := range normalized.Timestamps {
+			sample := pollerSample{
+				timestamp: normalized.Timestamps[j],
+				source:    i,
+				value:     normalized.UptimeValues[j],
+			}
+			if normalized.Exceptions != nil {
+				sample.exception = normalized.Exceptions[j]
+				hasExceptions = true
+			}
+			samples = append(samples, sample)
+		}
+	}
+	sort.SliceStable(samples, func(a, b int) bool {
+		return samples[a].timestamp < samples[b].timestamp
+	})
+	merged := UptimeSeries{}
+	exceptions := []bool{}
+	for i := 0; i < len(samples); {
+		// Collect one sample per source within the align window
+		slotTime := samples[i].timestamp
+		seen := map[int]bool{}
+		j := i
+		for j < len(samples) && samples[j].timestamp-slotTime <= options.AlignWindow && !seen[samples[j].source] {
+			seen[samples[j].source] = true
+			j++
+		}
+		slot := samples[i:j]
+		upSources := []int{}
+		downSources := []int{}
+		exception := false
+		value := 0
+		for _, sample := range slot {
+			exception = exception || sample.exception
+			if sample.value <= 0 {
+				downSources = append(downSources, sample.source)
+				continue
+			}
+			upSources = append(upSources, sample.source)
+			shifted := sample.value - int(sample.timestamp-slotTime)
+			if shifted < 1 {
+				shifted = 1
+			}
+			if shifted > value {
+				value = shifted
+			}
+		}
+		var up bool
+		switch options.Rule {
+		case ConsensusAnyUp:
+			up = len(upSources) > 0
+		case ConsensusAllUp:
+			up = len(downSources) == 0
+		case ConsensusMajority:
+			up = len(upSources)*2 > len(slot)
+		}
+		if !up {
+			value = 0
+		}
+		if len(upSources) > 0 && len(downSources) > 0 {
+			report.Disagreements = append(report.Disagreements, PollerDisagreement{
+				Timestamp:   slotTime,
+				UpSources:   upSources,
+				DownSources: downSources,
+				Up:          up,
+			})
+			overruled := upSources
+			if up {
+				overruled = downSources
+			}
+			for _, source := range overruled {
+				report.Overruled[source]++
+			}
+		}
+		merged.Timestamps = append(merged.Timestamps, slotTime)
+		merged.UptimeValues = append(merged.UptimeValues, value)
+		exceptions = append(exceptions, exception)
+		report.Slots++
+		i = j
+	}
+	if hasExceptions {
+		merged.Exceptions = exceptions
+	}
+	return merged, report, nil
+}
@@ -0,0 +1,104 @@
+package slacalculator_test
+
+import (
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestMergeSeries(t *testing.T) {
+	pollers := []slacalc.UptimeSeries{
+		{
+			Timestamps:   []int64{10100, 10200, 10300, 10400},
+			UptimeValues: []int{100, 0, 300, 400},
+		},
+		{
+			Timestamps:   []int64{10102, 10202, 10302, 10402},
+			UptimeValues: []int{102, 202, 0, 402},
+		},
+		{
+			Timestamps:   []int64{10101, 10201, 10301, 10401},
+			UptimeValues: []int{101, 201, 301, 0},
+		},
+	}
+	t.Run("Any Up", func(t *testing.T) {
+		merged, report, err := slacalc.MergeSeries(pollers, slacalc.MergeOptions{Rule: slacalc.ConsensusAnyUp, AlignWindow: 5})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if report.Slots != 4 {
+			t.Fatalf("The amount of slots is %v instead of 4", report.Slots)
+		}
+		expectedValues := []int{100, 200, 300, 400}
+		for i := range expectedValues {
+			if merged.UptimeValues[i] != expectedValues[i] {
+				t.Errorf("The merged value %v is %v instead of %v", i, merged.UptimeValues[i], expectedValues[i])
+			}
+		}
+		if len(report.Disagreements) != 3 {
+			t.Errorf("The amount of disagreements is %v instead of 3", len(report.Disagreements))
+		}
+		for i, overruled := range report.Overruled {
+			if overruled != 1 {
+				t.Errorf("The source %v is overruled %v times instead of 1", i, overruled)
+			}
+		}
+	})
+	t.Run("All Up", func(t *testing.T) {
+		merged, _, err := slacalc.MergeSeries(pollers, slacalc.MergeOptions{Rule: slacalc.ConsensusAllUp, AlignWindow: 5})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		expectedValues := []int{100, 0, 0, 0}
+		for i := range expectedValues {
+			if merged.UptimeValues[i] != expectedValues[i] {
+				t.Errorf("The merged value %v is %v instead of %v", i, merged.UptimeValues[i], expectedValues[i])
+			}
+		}
+	})
+	t.Run("Majority", func(t *testing.T) {
+		merged, _, err := slacalc.MergeSeries(pollers[:2], slacalc.MergeOptions{Rule: slacalc.ConsensusMajority, AlignWindow: 5})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		expectedValues := []int{100, 0, 0, 400}
+		for i := range expectedValues {
+			if merged.UptimeValues[i] != expectedValues[i] {
+				t.Errorf("The merged value %v is %v instead of %v", i, merged.UptimeValues[i], expectedValues[i])
+			}
+		}
+	})
+	t.Run("Exceptions", func(t *testing.T) {
+		merged, _, err := slacalc.MergeSeries(pollers, slacalc.MergeOptions{Rule: slacalc.ConsensusAnyUp, AlignWindow: 5})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if merged.Exceptions != nil {
+			t.Errorf("The merged exceptions should be nil without source exceptions")
+		}
+		withException := append([]slacalc.UptimeSeries{}, pollers...)
+		withException[2].Exceptions = []bool{false, true, false, false}
+		merged, _, err = slacalc.MergeSeries(withException, slacalc.MergeOptions{Rule: slacalc.ConsensusAnyUp, AlignWindow: 5})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(merged.Exceptions) != 4 || !merged.Exceptions[1] || merged.Exceptions[0] {
+			t.Errorf("The merged exceptions are %v instead of [false true false false]", merged.Exceptions)
+		}
+	})
+	t.Run("Without Align Window", func(t *testing.T) {
+		_, report, err := slacalc.MergeSeries(pollers, slacalc.MergeOptions{Rule: slacalc.ConsensusAnyUp})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if report.Slots != 12 {
+			t.Errorf("The amount of slots is %v instead of 12", report.Slots)
+		}
+	})
+	t.Run("No Source", func(t *testing.T) {
+		_, _, err := slacalc.MergeSeries(nil, slacalc.MergeOptions{})
+		if err == nil {
+			t.Fatalf("Error should be occured.")
+		}
+	})
+}
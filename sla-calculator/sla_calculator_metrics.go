@@ -0,0 +1,192 @@
+package slacalculator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FORMULA_BAKTI_SQF is the formula label of CalcBaktiSqf results. It is not a registered
+// formula, as it requires SQF data besides the uptime series.
+const FORMULA_BAKTI_SQF = "bakti_sqf"
+
+// OPENMETRICS_CONTENT_TYPE is the content type of the OpenMetrics text exposition.
+const OPENMETRICS_CONTENT_TYPE = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+type metricDefinition struct {
+	name string
+	unit string
+	help string
+}
+
+var metricDefinitions = []metricDefinition{
+	{"availability_ratio", "ratio", "Availability of the device by the formula."},
+	{"uptime_seconds", "seconds", "Counted uptime of the device by the formula."},
+	{"downtime_seconds", "seconds", "Downtime of the device by the formula."},
+	{"open_seconds", "seconds", "Duration of the open state of the device."},
+	{"bakti_link_failure_seconds", "seconds", "Link failure duration of the BAKTI availability."},
+	{"bakti_restitution_seconds", "seconds", "Restitution duration of the BAKTI availability."},
+	{"bakti_rain_quota_used_seconds", "seconds", "Rain quota used by the BAKTI availability considering SQF data."},
+	{"error_budget_remaining_seconds", "seconds", "Remaining error budget of the SLO, negative when exhausted."},
+	{"error_budget_remaining_ratio", "ratio", "Remaining error budget of the SLO relative to the allowed downtime."},
+}
+
+type metricLabels struct {
+	device  string
+	formula string
+}
+
+// MetricsCollector holds the latest SLA figures of every device and exposes them as OpenMetrics text.
+// It is safe for concurrent use.
+type MetricsCollector struct {
+	namespace string
+	mutex     sync.RWMutex
+	values    map[string]map[metricLabels]float64
+}
+
+// NewMetricsCollector returns a collector whose metric names are prefixed by the namespace,
+// empty means "sla".
+func NewMetricsCollector(namespace string) *MetricsCollector {
+	if namespace == "" {
+		namespace = "sla"
+	}
+	return &MetricsCollector{
+		namespace: namespace,
+		values:    map[string]map[metricLabels]float64{},
+	}
+}
+
+func (c *MetricsCollector) set(name, device, formula string, value float64) {
+	if c.values[name] == nil {
+		c.values[name] = map[metricLabels]float64{}
+	}
+	c.values[name][metricLabels{device, formula}] = value
+}
+
+// setAvailability sets the availability of the device by the formula, or deletes it
+// when the availability is DEFAULT_FLOAT_VALUE, ie: the formula could not be calculated.
+func (c *MetricsCollector) setAvailability(device, formula string, value float64) {
+	if value == DEFAULT_FLOAT_VALUE {
+		delete(c.values["availability_ratio"], metricLabels{device, formula})
+		return
+	}
+	c.set("availability_ratio", device, formula, value)
+}
+
+// SetFormulaResult sets the availability, uptime and downtime of the device by the formula of the result.
+func (c *MetricsCollector) SetFormulaResult(device string, result *FormulaResult) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.setAvailability(device, result.Formula, result.Availability)
+	c.set("uptime_seconds", device, result.Formula, float64(result.Uptime))
+	c.set("downtime_seconds", device, result.Formula, float64(result.Downtime))
+}
+
+// SetAvailabilitySummary sets the availability of every generic formula of the summary, and its
+// uptime, downtime and open seconds under the uptime formula.
+func (c *MetricsCollector) SetAvailabilitySummary(device string, summary AvailabilitySummary) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.setAvailability(device, FORMULA_SNMP, summary.SNMPAvailability)
+	c.setAvailability(device, FORMULA_UPTIME, summary.UptimeAvailability)
+	c.setAvailability(device, FORMULA_SLA1, summary.SLA1Availability)
+	c.setAvailability(device, FORMULA_SLA2, summary.SLA2Availability)
+	c.set("uptime_seconds", device, FORMULA_UPTIME, float64(summary.Uptime))
+	c.set("downtime_seconds", device, FORMULA_UPTIME, float64(summary.Downtime))
+	c.set("open_seconds", device, FORMULA_UPTIME, float64(summary.Open))
+}
+
+// SetBakti1Availability sets the BAKTI availability figures of the device.
+func (c *MetricsCollector) SetBakti1Availability(device string, availability *Bakti1Availability) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.setAvailability(device, FORMULA_BAKTI1, availability.Availability)
+	c.set("open_seconds", device, FORMULA_BAKTI1, float64(availability.OpenDuration))
+	c.set("bakti_link_failure_seconds", device, FORMULA_BAKTI1, float64(availability.LinkFailureDuration))
+	c.set("bakti_restitution_seconds", device, FORMULA_BAKTI1, float64(availability.RestitutionDuration))
+}
+
+// SetBaktiSqfAvailability sets the BAKTI availability figures considering SQF data of the device.
+func (c *MetricsCollector) SetBaktiSqfAvailability(device string, availability *BaktiSqfAvailability) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.setAvailability(device, FORMULA_BAKTI_SQF, availability.Availability)
+	c.set("open_seconds", device, FORMULA_BAKTI_SQF, float64(availability.OpenDuration))
+	c.set("bakti_link_failure_seconds", device, FORMULA_BAKTI_SQF, float64(availability.LinkFailureDuration))
+	c.set("bakti_restitution_seconds", device, FORMULA_BAKTI_SQF, float64(availability.RestitutionDuration))
+	c.set("bakti_rain_quota_used_seconds", device, FORMULA_BAKTI_SQF, float64(availability.RainQuotaUsed))
+}
+
+// SetErrorBudget sets the remaining error budget of the device at the point, ie: the last point
+// returned by TrackErrorBudget.
+func (c *MetricsCollector) SetErrorBudget(device string, slo SLO, point ErrorBudgetPoint) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.set("error_budget_remaining_seconds", device, slo.Formula, float64(point.RemainingBudget))
+	c.set("error_budget_remaining_ratio", device, slo.Formula, point.RemainingRatio)
+}
+
+// Remove removes every figure of the device.
+func (c *MetricsCollector) Remove(device string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, values := range c.values {
+		for labels := range values {
+			if labels.device == device {
+				delete(values, labels)
+			}
+		}
+	}
+}
+
+func escapeMetricLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// WriteTo writes the OpenMetrics text exposition of the figures, sorted by device and formula.
+func (c *MetricsCollector) WriteTo(w io.Writer) (int64, error) {
+	c.mutex.RLock()
+	buf := bytes.Buffer{}
+	for _, definition := range metricDefinitions {
+		values := c.values[definition.name]
+		if len(values) <= 0 {
+			continue
+		}
+		name := c.namespace + "_" + definition.name
+		fmt.Fprintf(&buf, "# TYPE %v gauge\n", name)
+		fmt.Fprintf(&buf, "# UNIT %v %v\n", name, definition.unit)
+		fmt.Fprintf(&buf, "# HELP %v %v\n", name, definition.help)
+		labelsList := []metricLabels{}
+		for labels := range values {
+			labelsList = append(labelsList, labels)
+		}
+		sort.Slice(labelsList, func(i, j int) bool {
+			if labelsList[i].device != labelsList[j].device {
+				return labelsList[i].device < labelsList[j].device
+			}
+			return labelsList[i].formula < labelsList[j].formula
+		})
+		for _, labels := range labelsList {
+			fmt.Fprintf(&buf, "%v{device=\"%v\",formula=\"%v\"} %v\n", name,
+				escapeMetricLabel(labels.device), escapeMetricLabel(labels.formula), formatMetricValue(values[labels]))
+		}
+	}
+	c.mutex.RUnlock()
+	buf.WriteString("# EOF\n")
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP serves the OpenMetrics text exposition, so the collector can be scraped by Prometheus.
+func (c *MetricsCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", OPENMETRICS_CONTENT_TYPE)
+	c.WriteTo(w)
+}
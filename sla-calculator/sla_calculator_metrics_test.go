@@ -0,0 +1,103 @@
+package slacalculator_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	collector := slacalc.NewMetricsCollector("")
+	collector.SetFormulaResult(`site "a"`, &slacalc.FormulaResult{Formula: slacalc.FORMULA_SLA1, Availability: 0.75, Uptime: 2250, Downtime: 750})
+	collector.SetAvailabilitySummary("site-b", slacalc.AvailabilitySummary{
+		SNMPAvailability:   0.5,
+		UptimeAvailability: 0.6,
+		SLA1Availability:   0.7,
+		SLA2Availability:   slacalc.DEFAULT_FLOAT_VALUE,
+		Uptime:             1800,
+		Downtime:           1200,
+		Open:               300,
+	})
+	collector.SetBakti1Availability("site-b", &slacalc.Bakti1Availability{Availability: 0.9, LinkFailureDuration: 100, RestitutionDuration: 200, OpenDuration: 50})
+	collector.SetBaktiSqfAvailability("site-b", &slacalc.BaktiSqfAvailability{Availability: 0.95, RainQuotaUsed: 60})
+	collector.SetErrorBudget("site-b", slacalc.SLO{Formula: slacalc.FORMULA_SLA1, Target: 0.9}, slacalc.ErrorBudgetPoint{RemainingBudget: -900, RemainingRatio: -3})
+	t.Run("WriteTo", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		n, err := collector.WriteTo(&buf)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if n != int64(buf.Len()) {
+			t.Errorf("The written length is %v instead of %v", n, buf.Len())
+		}
+		output := buf.String()
+		expectedLines := []string{
+			"# TYPE sla_availability_ratio gauge",
+			"# UNIT sla_availability_ratio ratio",
+			`sla_availability_ratio{device="site \"a\"",formula="sla1"} 0.75`,
+			`sla_availability_ratio{device="site-b",formula="bakti_sqf"} 0.95`,
+			`sla_open_seconds{device="site-b",formula="uptime"} 300`,
+			`sla_bakti_link_failure_seconds{device="site-b",formula="bakti1"} 100`,
+			`sla_bakti_rain_quota_used_seconds{device="site-b",formula="bakti_sqf"} 60`,
+			`sla_error_budget_remaining_seconds{device="site-b",formula="sla1"} -900`,
+		}
+		for _, line := range expectedLines {
+			if !strings.Contains(output, line+"\n") {
+				t.Errorf("The line %q is missing from:\n%v", line, output)
+			}
+		}
+		if strings.Contains(output, `formula="sla2"`) {
+			t.Errorf("The sla2 availability should not be exposed without exceptions")
+		}
+		if !strings.HasSuffix(output, "# EOF\n") {
+			t.Errorf("The exposition should end with # EOF")
+		}
+	})
+	t.Run("Unavailable Formula", func(t *testing.T) {
+		collector := slacalc.NewMetricsCollector("")
+		collector.SetAvailabilitySummary("site-c", slacalc.AvailabilitySummary{SNMPAvailability: 0.5, SLA1Availability: 0.7, SLA2Availability: 0.8})
+		collector.SetAvailabilitySummary("site-c", slacalc.AvailabilitySummary{
+			SNMPAvailability:   slacalc.DEFAULT_FLOAT_VALUE,
+			UptimeAvailability: slacalc.DEFAULT_FLOAT_VALUE,
+			SLA1Availability:   0.7,
+			SLA2Availability:   slacalc.DEFAULT_FLOAT_VALUE,
+		})
+		buf := bytes.Buffer{}
+		collector.WriteTo(&buf)
+		output := buf.String()
+		if strings.Contains(output, " -1\n") {
+			t.Errorf("The default value should not be exposed as availability:\n%v", output)
+		}
+		for _, formula := range []string{slacalc.FORMULA_SNMP, slacalc.FORMULA_UPTIME, slacalc.FORMULA_SLA2} {
+			if strings.Contains(output, `sla_availability_ratio{device="site-c",formula="`+formula+`"}`) {
+				t.Errorf("The stale %v availability should be deleted:\n%v", formula, output)
+			}
+		}
+		if !strings.Contains(output, `sla_availability_ratio{device="site-c",formula="sla1"} 0.7`+"\n") {
+			t.Errorf("The sla1 availability is missing from:\n%v", output)
+		}
+	})
+	t.Run("ServeHTTP", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		if recorder.Header().Get("Content-Type") != slacalc.OPENMETRICS_CONTENT_TYPE {
+			t.Errorf("The content type is %v instead of %v", recorder.Header().Get("Content-Type"), slacalc.OPENMETRICS_CONTENT_TYPE)
+		}
+		body, _ := ioutil.ReadAll(recorder.Body)
+		if !strings.Contains(string(body), "sla_uptime_seconds") {
+			t.Errorf("The body should expose the uptime seconds")
+		}
+	})
+	t.Run("Remove", func(t *testing.T) {
+		collector.Remove("site-b")
+		buf := bytes.Buffer{}
+		collector.WriteTo(&buf)
+		if strings.Contains(buf.String(), "site-b") || strings.Contains(buf.String(), "sla_open_seconds") {
+			t.Errorf("The figures of site-b should be removed:\n%v", buf.String())
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package slacalculator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DuplicateRule decides which sample is kept when several samples share the same timestamp.
+type DuplicateRule int
+
+const (
+	// DuplicateKeepMax keeps the sample with the highest uptime value.
+	DuplicateKeepMax DuplicateRule = iota
+	// DuplicateKeepFirst keeps the sample which comes first in the input.
+	DuplicateKeepFirst
+	// DuplicateKeepLast keeps the sample which comes last in the input.
+	DuplicateKeepLast
+)
+
+// UptimeSeries holds the parallel slices accepted by NewUptimeSLACalculator.
+type UptimeSeries struct {
+	Timestamps   []int64
+	UptimeValues []int
+	Exceptions   []bool
+}
+
+// NewCalculator returns the uptime calculator object of the series.
+func (s UptimeSeries) NewCalculator(startTime, endTime int64, toleranceDeltaRatio float64) (*UptimeSLACalculator, error) {
+	return NewUptimeSLACalculator(startTime, endTime, s.Timestamps, s.UptimeValues, toleranceDeltaRatio, s.Exceptions)
+}
+
+// NormalizationReport explains what NormalizeSeries changed on the inputted series.
+type NormalizationReport struct {
+	// Reordered is the amount of samples which are not in their sorted position.
+	Reordered int
+	// DuplicatesRemoved is the amount of samples dropped because of a duplicate timestamp.
+	DuplicatesRemoved int
+	// DuplicateTimestamps lists every timestamp which had more than one sample.
+	DuplicateTimestamps []int64
+}
+
+// Changed tells whether the normalization modified the series.
+func (r NormalizationReport) Changed() bool {
+	return r.Reordered > 0 || r.DuplicatesRemoved > 0
+}
+
+// NormalizeSeries stable-sorts the samples by timestamp and resolves duplicate timestamps
+// according to the rule. The exception of a resolved timestamp is set if any of its samples
+// is an exception. The inputted slices are not modified.
+func NormalizeSeries(series UptimeSeries, rule DuplicateRule) (UptimeSeries, NormalizationReport, error) {
+	report := NormalizationReport{}
+	if len(series.Timestamps) != len(series.UptimeValues) {
+		return UptimeSeries{}, report, fmt.Errorf("length of timestamps and uptime value is unmatched")
+	}
+	if series.Exceptions != nil && len(series.Timestamps) != len(series.Exceptions) {
+		return UptimeSeries{}, report, fmt.Errorf("length of timestamps and exceptions is unmatched")
+	}
+	if rule != DuplicateKeepMax && rule != DuplicateKeepFirst && rule != DuplicateKeepLast {
+		return UptimeSeries{}, report, fmt.Errorf("unknown duplicate rule: %v", rule)
+	}
+	order := make([]int, len(series.Timestamps))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return series.Timestamps[order[a]] < series.Timestamps[order[b]]
+	})
+	for i := range order {
+		if order[i] != i {
+			report.Reordered++
+		}
+	}
+	normalized := UptimeSeries{}
+	if series.Exceptions != nil {
+		normalized.Exceptions = []bool{}
+	}
+	for i := 0; i < len(order); {
+		// Collect every sample sharing the same timestamp
+		j := i
+		for j < len(order) && series.Timestamps[order[j]] == series.Timestamps[order[i]] {
+			j++
+		}
+		kept := order[i]
+		exception := false
+		for _, idx := range order[i:j] {
+			switch rule {
+			case DuplicateKeepMax:
+				if series.UptimeValues[idx] > series.UptimeValues[kept] {
+					kept = idx
+				}
+			case DuplicateKeepLast:
+				kept = idx
+			}
+			if series.Exceptions != nil && series.Exceptions[idx] {
+				exception = true
+			}
+		}
+		if j-i > 1 {
+			report.DuplicatesRemoved += j - i - 1
+			report.DuplicateTimestamps = append(report.DuplicateTimestamps, series.Timestamps[kept])
+		}
+		normalized.Timestamps = append(normalized.Timestamps, series.Timestamps[kept])
+		normalized.UptimeValues = append(normalized.UptimeValues, series.UptimeValues[kept])
+		if series.Exceptions != nil {
+			normalized.Exceptions = append(normalized.Exceptions, exception)
+		}
+		i = j
+	}
+	return normalized, report, nil
+}
+
+// NewNormalizedUptimeSLACalculator normalizes the series before returning the uptime calculator object,
+// so unordered or duplicated samples are accepted.
+func NewNormalizedUptimeSLACalculator(startTime, endTime int64, timestamps []int64, uptimeValues []int, toleranceDeltaRatio float64, exceptions []bool, rule DuplicateRule) (*UptimeSLACalculator, NormalizationReport, error) {
+	series, report, err := NormalizeSeries(UptimeSeries{timestamps, uptimeValues, exceptions}, rule)
+	if err != nil {
+		return nil, report, err
+	}
+	calc, err := series.NewCalculator(startTime, endTime, toleranceDeltaRatio)
+	if err != nil {
+		return nil, report, err
+	}
+	return calc, report, nil
+}
@@ -0,0 +1,92 @@
+package slacalculator_test
+
+import (
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestNormalizeSeries(t *testing.T) {
+	series := slacalc.UptimeSeries{
+		Timestamps:   []int64{10200, 10100, 10300, 10200, 10400},
+		UptimeValues: []int{200, 100, 300, 150, 400},
+		Exceptions:   []bool{false, false, false, true, false},
+	}
+	t.Run("Keep Max", func(t *testing.T) {
+		normalized, report, err := slacalc.NormalizeSeries(series, slacalc.DuplicateKeepMax)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		expectedTimestamps := []int64{10100, 10200, 10300, 10400}
+		expectedValues := []int{100, 200, 300, 400}
+		for i := range expectedTimestamps {
+			if normalized.Timestamps[i] != expectedTimestamps[i] || normalized.UptimeValues[i] != expectedValues[i] {
+				t.Errorf("Sample %v is (%v, %v) instead of (%v, %v)", i, normalized.Timestamps[i], normalized.UptimeValues[i], expectedTimestamps[i], expectedValues[i])
+			}
+		}
+		if !normalized.Exceptions[1] {
+			t.Errorf("The exception of the duplicated timestamp should be kept")
+		}
+		if report.DuplicatesRemoved != 1 {
+			t.Errorf("The amount of removed duplicates is %v instead of 1", report.DuplicatesRemoved)
+		}
+		if len(report.DuplicateTimestamps) != 1 || report.DuplicateTimestamps[0] != 10200 {
+			t.Errorf("The duplicate timestamps are %v instead of [10200]", report.DuplicateTimestamps)
+		}
+		if !report.Changed() || report.Reordered == 0 {
+			t.Errorf("The report should tell the series is reordered")
+		}
+	})
+	t.Run("Keep First", func(t *testing.T) {
+		normalized, _, err := slacalc.NormalizeSeries(series, slacalc.DuplicateKeepFirst)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if normalized.UptimeValues[1] != 200 {
+			t.Errorf("The kept value is %v instead of 200", normalized.UptimeValues[1])
+		}
+	})
+	t.Run("Keep Last", func(t *testing.T) {
+		normalized, _, err := slacalc.NormalizeSeries(series, slacalc.DuplicateKeepLast)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if normalized.UptimeValues[1] != 150 {
+			t.Errorf("The kept value is %v instead of 150", normalized.UptimeValues[1])
+		}
+	})
+	t.Run("Ordered Series", func(t *testing.T) {
+		ordered := slacalc.UptimeSeries{
+			Timestamps:   []int64{10100, 10200},
+			UptimeValues: []int{100, 200},
+		}
+		normalized, report, err := slacalc.NormalizeSeries(ordered, slacalc.DuplicateKeepMax)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if report.Changed() {
+			t.Errorf("The report should not tell any change: %+v", report)
+		}
+		if normalized.Exceptions != nil {
+			t.Errorf("Nil exceptions should be kept nil")
+		}
+	})
+	t.Run("Unmatched Length", func(t *testing.T) {
+		_, _, err := slacalc.NormalizeSeries(slacalc.UptimeSeries{
+			Timestamps:   []int64{10100, 10200},
+			UptimeValues: []int{100},
+		}, slacalc.DuplicateKeepMax)
+		if err == nil {
+			t.Fatalf("Error should be occured.")
+		}
+	})
+	t.Run("Calculator", func(t *testing.T) {
+		calc, _, err := slacalc.NewNormalizedUptimeSLACalculator(10000, 10400, series.Timestamps, series.UptimeValues, toleranceDeltaRatio, series.Exceptions, slacalc.DuplicateKeepMax)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if avai := calc.CalculateUptimeAvailability(); avai != 1 {
+			t.Errorf("The calculated Uptime Availability value is %v, instead of 1", avai)
+		}
+	})
+}
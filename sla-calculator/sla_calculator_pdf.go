@@ -0,0 +1,369 @@
+package slacalculator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 40
+	pdfLineHeight = 14
+)
+
+const (
+	// PDF_SECTION_HEADER is the section of the title, device, contract, period and target.
+	PDF_SECTION_HEADER = "header"
+	// PDF_SECTION_SUMMARY is the availability summary table of the formulas.
+	PDF_SECTION_SUMMARY = "summary"
+	// PDF_SECTION_TIMELINE is the timeline bar coloured by state along with its legend.
+	PDF_SECTION_TIMELINE = "timeline"
+	// PDF_SECTION_OUTAGES is the outage table.
+	PDF_SECTION_OUTAGES = "outages"
+)
+
+// The columns of the summary table, see PDFReportOptions.SummaryColumns.
+const (
+	PDF_COLUMN_FORMULA        = "formula"
+	PDF_COLUMN_AVAILABILITY   = "availability"
+	PDF_COLUMN_TARGET         = "target"
+	PDF_COLUMN_COMPLIANCE     = "compliance"
+	PDF_COLUMN_UPTIME         = "uptime"
+	PDF_COLUMN_DOWNTIME       = "downtime"
+	PDF_COLUMN_PENALTY_TIER   = "penalty_tier"
+	PDF_COLUMN_PENALTY_AMOUNT = "penalty_amount"
+)
+
+// PDFReportOptions customises the layout of WritePDF, the zero value is the default layout.
+type PDFReportOptions struct {
+	// Sections lists the sections in their order, empty means every section in the order of PDF_SECTION_*.
+	Sections []string
+	// SummaryColumns lists the columns of the summary table in their order, empty means every column
+	// in the order of PDF_COLUMN_*.
+	SummaryColumns []string
+	// StateColors overrides the colour of the timeline states, as #rrggbb, ie: "up": "#00ff00".
+	StateColors map[string]string
+}
+
+// pdfStateColors are the colours of the timeline states, matching the default HTML template.
+var pdfStateColors = map[string]string{
+	REPORT_STATE_UP:            "#2e7d32",
+	REPORT_STATE_DOWN:          "#c62828",
+	REPORT_STATE_OPEN:          "#9e9e9e",
+	REPORT_STATE_LINK_FAILURE:  "#ef6c00",
+	REPORT_STATE_POWER_FAILURE: "#6a1b9a",
+	REPORT_STATE_EXCLUDED:      "#1565c0",
+}
+
+var pdfSections = []string{PDF_SECTION_HEADER, PDF_SECTION_SUMMARY, PDF_SECTION_TIMELINE, PDF_SECTION_OUTAGES}
+
+// pdfSummaryColumn is a column of the summary table, its value is empty when the line has no such value.
+type pdfSummaryColumn struct {
+	title string
+	width float64
+	value func(line SLAReportLine) string
+}
+
+var pdfSummaryColumnOrder = []string{
+	PDF_COLUMN_FORMULA, PDF_COLUMN_AVAILABILITY, PDF_COLUMN_TARGET, PDF_COLUMN_COMPLIANCE,
+	PDF_COLUMN_UPTIME, PDF_COLUMN_DOWNTIME, PDF_COLUMN_PENALTY_TIER, PDF_COLUMN_PENALTY_AMOUNT,
+}
+
+var pdfSummaryColumns = map[string]pdfSummaryColumn{
+	PDF_COLUMN_FORMULA: {"Formula", 75, func(line SLAReportLine) string {
+		return line.Formula
+	}},
+	PDF_COLUMN_AVAILABILITY: {"Availability", 70, func(line SLAReportLine) string {
+		return formatReportPercent(line.Availability)
+	}},
+	PDF_COLUMN_TARGET: {"Target", 55, func(line SLAReportLine) string {
+		return formatReportPercent(line.Target)
+	}},
+	PDF_COLUMN_COMPLIANCE: {"Compliance", 80, func(line SLAReportLine) string {
+		if line.Compliant {
+			return "Compliant"
+		}
+		return "Not Compliant"
+	}},
+	PDF_COLUMN_UPTIME: {"Uptime", 60, func(line SLAReportLine) string {
+		return FormatReportDuration(line.Uptime)
+	}},
+	PDF_COLUMN_DOWNTIME: {"Downtime", 60, func(line SLAReportLine) string {
+		return FormatReportDuration(line.Downtime)
+	}},
+	PDF_COLUMN_PENALTY_TIER: {"Tier", 60, func(line SLAReportLine) string {
+		if line.Credit == nil {
+			return ""
+		}
+		return line.Credit.Tier
+	}},
+	PDF_COLUMN_PENALTY_AMOUNT: {"Penalty", 55, func(line SLAReportLine) string {
+		if line.Credit == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.2f", line.Credit.Amount)
+	}},
+}
+
+// parsePDFColor parses a #rrggbb colour into its RGB components between 0 and 1.
+func parsePDFColor(color string) ([3]float64, error) {
+	rgb := [3]float64{}
+	if len(color) != 7 || color[0] != '#' {
+		return rgb, fmt.Errorf("colour should be #rrggbb: %v", color)
+	}
+	for i := range rgb {
+		component, err := strconv.ParseUint(color[1+i*2:3+i*2], 16, 8)
+		if err != nil {
+			return rgb, fmt.Errorf("colour should be #rrggbb: %v", color)
+		}
+		rgb[i] = float64(component) / 255
+	}
+	return rgb, nil
+}
+
+// pdfLayout is the validated layout of PDFReportOptions.
+type pdfLayout struct {
+	sections []string
+	columns  []pdfSummaryColumn
+	colors   map[string][3]float64
+}
+
+func (o *PDFReportOptions) layout() (*pdfLayout, error) {
+	if o == nil {
+		o = &PDFReportOptions{}
+	}
+	layout := pdfLayout{sections: o.Sections, colors: map[string][3]float64{}}
+	if len(layout.sections) <= 0 {
+		layout.sections = pdfSections
+	}
+	for _, section := range layout.sections {
+		known := false
+		for _, pdfSection := range pdfSections {
+			known = known || section == pdfSection
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown pdf section: %v", section)
+		}
+	}
+	columns := o.SummaryColumns
+	if len(columns) <= 0 {
+		columns = pdfSummaryColumnOrder
+	}
+	width := 0.0
+	for _, name := range columns {
+		column, ok := pdfSummaryColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown pdf summary column: %v", name)
+		}
+		width += column.width
+		layout.columns = append(layout.columns, column)
+	}
+	if width > pdfPageWidth-pdfMargin*2 {
+		return nil, fmt.Errorf("pdf summary columns are wider than the page: %v", columns)
+	}
+	for state, color := range pdfStateColors {
+		layout.colors[state], _ = parsePDFColor(color)
+	}
+	for state, color := range o.StateColors {
+		rgb, err := parsePDFColor(color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid colour of state %v: %v", state, err)
+		}
+		layout.colors[state] = rgb
+	}
+	return &layout, nil
+}
+
+// pdfDocument lays out text and rectangles top-down on A4 pages.
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func (d *pdfDocument) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// reserve starts a new page when the height does not fit in the current page.
+func (d *pdfDocument) reserve(height float64) {
+	if len(d.pages) <= 0 || d.y-height < pdfMargin {
+		d.newPage()
+	}
+}
+
+func escapePDFText(text string) string {
+	buf := strings.Builder{}
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			buf.WriteRune('\\')
+			buf.WriteRune(r)
+		case r < 32 || r > 126:
+			buf.WriteRune('?')
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+func (d *pdfDocument) text(x float64, bold bool, size float64, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.pages[len(d.pages)-1], "BT /%v %v Tf %v %v Td (%v) Tj ET\n", font, size, x, d.y, escapePDFText(text))
+}
+
+func (d *pdfDocument) rect(x, y, width, height float64, color [3]float64) {
+	// The graphics state is saved, so the fill color does not leak to the following text
+	fmt.Fprintf(d.pages[len(d.pages)-1], "q %.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f Q\n", color[0], color[1], color[2], x, y, width, height)
+}
+
+// row writes the cells at the column positions as a line.
+func (d *pdfDocument) row(columns []float64, bold bool, cells ...string) {
+	d.reserve(pdfLineHeight)
+	for i, cell := range cells {
+		d.text(columns[i], bold, 9, cell)
+	}
+	d.y -= pdfLineHeight
+}
+
+func (d *pdfDocument) heading(text string) {
+	d.reserve(pdfLineHeight * 3)
+	d.y -= pdfLineHeight / 2
+	d.text(pdfMargin, true, 12, text)
+	d.y -= pdfLineHeight * 1.5
+}
+
+// writeTo writes the pages as a PDF file with the standard Helvetica fonts.
+func (d *pdfDocument) writeTo(w io.Writer) error {
+	buf := bytes.Buffer{}
+	offsets := []int{}
+	object := func(content string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%v 0 obj\n%v\nendobj\n", len(offsets), content)
+	}
+	buf.WriteString("%PDF-1.4\n")
+	kids := []string{}
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%v 0 R", 5+i*2))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%v] /Count %v >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %v %v] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %v 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+i*2))
+		object(fmt.Sprintf("<< /Length %v >>\nstream\n%vendstream", page.Len(), page.String()))
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %v\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %v /Root 1 0 R >>\nstartxref\n%v\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// WritePDF writes the report as a printable A4 PDF with the header, the availability summary,
+// the timeline bar coloured by state and the outage table. Nil options means the default layout.
+func (r *SLAReport) WritePDF(w io.Writer, options *PDFReportOptions) error {
+	layout, err := options.layout()
+	if err != nil {
+		return err
+	}
+	d := &pdfDocument{}
+	d.newPage()
+	for _, section := range layout.sections {
+		switch section {
+		case PDF_SECTION_HEADER:
+			r.writePDFHeader(d)
+		case PDF_SECTION_SUMMARY:
+			r.writePDFSummary(d, layout.columns)
+		case PDF_SECTION_TIMELINE:
+			r.writePDFTimeline(d, layout.colors)
+		case PDF_SECTION_OUTAGES:
+			r.writePDFOutages(d)
+		}
+	}
+	return d.writeTo(w)
+}
+
+func (r *SLAReport) writePDFHeader(d *pdfDocument) {
+	d.reserve(pdfLineHeight * 6)
+	d.text(pdfMargin, true, 16, r.Title)
+	d.y -= pdfLineHeight * 2
+	header := [][2]string{{"Device", r.Device}}
+	if r.Contract != "" {
+		header = append(header, [2]string{"Contract", r.Contract})
+	}
+	header = append(header,
+		[2]string{"Period", r.FormatTime(r.PeriodStart) + " - " + r.FormatTime(r.PeriodEnd)},
+		[2]string{"Target", formatReportPercent(r.Target)},
+	)
+	for _, line := range header {
+		d.row([]float64{pdfMargin, pdfMargin + 70}, false, line[0], line[1])
+	}
+}
+
+func (r *SLAReport) writePDFSummary(d *pdfDocument, columns []pdfSummaryColumn) {
+	d.heading("Availability Summary")
+	positions := []float64{}
+	titles := []string{}
+	x := float64(pdfMargin)
+	for _, column := range columns {
+		positions = append(positions, x)
+		titles = append(titles, column.title)
+		x += column.width
+	}
+	d.row(positions, true, titles...)
+	for _, line := range r.Lines {
+		cells := []string{}
+		for _, column := range columns {
+			cells = append(cells, column.value(line))
+		}
+		d.row(positions, false, cells...)
+	}
+}
+
+func (r *SLAReport) writePDFTimeline(d *pdfDocument, colors map[string][3]float64) {
+	d.heading("Timeline")
+	d.reserve(pdfLineHeight * 3)
+	barWidth := float64(pdfPageWidth - pdfMargin*2)
+	for _, segment := range r.Segments() {
+		// A segment is placed at its start time, so the gaps of the timeline are left blank
+		x := pdfMargin + r.TimelineOffset(segment)*barWidth
+		d.rect(x, d.y-10, r.TimelineRatio(segment)*barWidth, 18, colors[segment.State])
+	}
+	d.y -= pdfLineHeight * 2
+	x := float64(pdfMargin)
+	for _, state := range reportStates {
+		d.rect(x, d.y-1, 8, 8, colors[state])
+		d.text(x+11, false, 8, state)
+		x += 85
+	}
+	d.y -= pdfLineHeight
+}
+
+func (r *SLAReport) writePDFOutages(d *pdfDocument) {
+	d.heading("Outages")
+	outages := r.Outages()
+	if len(outages) <= 0 {
+		d.row([]float64{pdfMargin}, false, "No outage.")
+		return
+	}
+	outageColumns := []float64{40, 160, 280, 360}
+	d.row(outageColumns, true, "Start", "End", "Duration", "State")
+	for _, outage := range outages {
+		d.row(outageColumns, false, r.FormatTime(outage.StartTime), r.FormatTime(outage.EndTime),
+			FormatReportDuration(outage.Duration()), outage.State)
+	}
+}
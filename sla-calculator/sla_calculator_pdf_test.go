@@ -0,0 +1,138 @@
+package slacalculator_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestWritePDF(t *testing.T) {
+	report := newBaktiSLAReport(t)
+	report.Contract = "Contract (A)"
+	buf := bytes.Buffer{}
+	if err := report.WritePDF(&buf, nil); err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	output := buf.String()
+	if !strings.HasPrefix(output, "%PDF-1.4\n") || !strings.HasSuffix(output, "%%EOF\n") {
+		t.Fatalf("The output is not a PDF file")
+	}
+	// Every xref entry should point to its object
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(output)
+	if match == nil {
+		t.Fatalf("The startxref is missing")
+	}
+	xref, _ := strconv.Atoi(match[1])
+	if !strings.HasPrefix(output[xref:], "xref\n") {
+		t.Fatalf("The startxref does not point to the xref table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(output[xref:], -1)
+	if len(entries) != 6 {
+		t.Errorf("The amount of objects is %v instead of 6", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if !strings.HasPrefix(output[offset:], fmt.Sprintf("%v 0 obj\n", i+1)) {
+			t.Errorf("The xref entry %v does not point to its object", i+1)
+		}
+	}
+	expectedParts := []string{
+		"(Monthly SLA Report) Tj",
+		"(Contract \\(A\\)) Tj",
+		"(site <1>) Tj",
+		"(73.333%) Tj",
+		"(power-failure) Tj",
+		"0.416 0.106 0.604 rg",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(output, part) {
+			t.Errorf("The PDF should contain %q", part)
+		}
+	}
+	t.Run("Customer Layout", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		err := report.WritePDF(&buf, &slacalc.PDFReportOptions{
+			Sections:       []string{slacalc.PDF_SECTION_HEADER, slacalc.PDF_SECTION_TIMELINE},
+			SummaryColumns: []string{slacalc.PDF_COLUMN_FORMULA, slacalc.PDF_COLUMN_AVAILABILITY},
+			StateColors:    map[string]string{slacalc.REPORT_STATE_POWER_FAILURE: "#000000"},
+		})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		output := buf.String()
+		if strings.Contains(output, "(Availability Summary) Tj") || strings.Contains(output, "(Outages) Tj") {
+			t.Errorf("The PDF should only contain the header and the timeline")
+		}
+		if !strings.Contains(output, "(Timeline) Tj") || !strings.Contains(output, "q 0.000 0.000 0.000 rg") {
+			t.Errorf("The timeline should use the customer colour")
+		}
+		buf.Reset()
+		err = report.WritePDF(&buf, &slacalc.PDFReportOptions{
+			Sections:       []string{slacalc.PDF_SECTION_SUMMARY},
+			SummaryColumns: []string{slacalc.PDF_COLUMN_AVAILABILITY, slacalc.PDF_COLUMN_FORMULA},
+		})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		// The availability column comes first at the left margin
+		if !regexp.MustCompile(`BT /F2 9 Tf 40 \S+ Td \(Availability\) Tj`).MatchString(buf.String()) || strings.Contains(buf.String(), "(Compliance) Tj") {
+			t.Errorf("The summary should only contain the customer columns")
+		}
+	})
+	t.Run("Invalid Layout", func(t *testing.T) {
+		for _, options := range []slacalc.PDFReportOptions{
+			{Sections: []string{"unknown"}},
+			{SummaryColumns: []string{"unknown"}},
+			{StateColors: map[string]string{slacalc.REPORT_STATE_UP: "green"}},
+			{SummaryColumns: []string{slacalc.PDF_COLUMN_FORMULA, slacalc.PDF_COLUMN_FORMULA, slacalc.PDF_COLUMN_FORMULA,
+				slacalc.PDF_COLUMN_FORMULA, slacalc.PDF_COLUMN_FORMULA, slacalc.PDF_COLUMN_FORMULA, slacalc.PDF_COLUMN_FORMULA,
+				slacalc.PDF_COLUMN_FORMULA}},
+		} {
+			if err := report.WritePDF(&bytes.Buffer{}, &options); err == nil {
+				t.Errorf("Error should be occured.")
+			}
+		}
+	})
+	t.Run("Timeline Gap", func(t *testing.T) {
+		report, err := slacalc.NewSLAReport(slacalc.SLAReportOptions{PeriodStart: 100, PeriodEnd: 500})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		report.Timeline = []slacalc.SLAReportSegment{{StartTime: 300, EndTime: 500, State: slacalc.REPORT_STATE_DOWN}}
+		buf := bytes.Buffer{}
+		if err := report.WritePDF(&buf, nil); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		// The bar of 515 points starts at the middle of the timeline
+		if !strings.Contains(buf.String(), "rg 297.50 ") {
+			t.Errorf("The down segment should start at the middle of the timeline")
+		}
+	})
+	t.Run("Pagination", func(t *testing.T) {
+		// Alternating states make 60 outages, which do not fit in the first page
+		report.Timeline = []slacalc.SLAReportSegment{}
+		for i := int64(0); i < 120; i++ {
+			state := slacalc.REPORT_STATE_UP
+			if i%2 == 1 {
+				state = slacalc.REPORT_STATE_DOWN
+			}
+			report.Timeline = append(report.Timeline, slacalc.SLAReportSegment{
+				StartTime: startTimeBakti + i*25,
+				EndTime:   startTimeBakti + (i+1)*25,
+				State:     state,
+			})
+		}
+		buf := bytes.Buffer{}
+		if err := report.WritePDF(&buf, nil); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if !strings.Contains(buf.String(), "/Count 2") {
+			t.Errorf("The outage table should continue on the second page")
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package slacalculator
+
+import (
+	"fmt"
+	"math"
+)
+
+// PenaltyKind decides how a PenaltySchedule computes the service credit.
+type PenaltyKind int
+
+const (
+	// PenaltyPercentage credits a percentage of the monthly fee according to the availability tier.
+	PenaltyPercentage PenaltyKind = iota
+	// PenaltyPerHour credits a percentage of the monthly fee for every charged downtime hour.
+	PenaltyPerHour
+)
+
+// PenaltyTier credits a ratio of the monthly fee when the availability is within [MinAvailability, MaxAvailability).
+type PenaltyTier struct {
+	Name            string
+	MinAvailability float64
+	MaxAvailability float64
+	// Credit is the credited ratio of the monthly fee, ie: 0.1 for 10%.
+	Credit float64
+}
+
+// PenaltySchedule is the service credit rule of a contract.
+type PenaltySchedule struct {
+	Kind PenaltyKind
+	// Tiers is used by PenaltyPercentage.
+	Tiers []PenaltyTier
+	// HourlyCredit is the credited ratio of the monthly fee per downtime hour, used by PenaltyPerHour.
+	HourlyCredit float64
+	// RoundUpHours counts every started downtime hour as a full hour.
+	RoundUpHours bool
+	// MaxCredit caps the credited ratio of the monthly fee, zero means no cap.
+	MaxCredit float64
+}
+
+// ServiceCredit is the credit given to the customer by a PenaltySchedule.
+type ServiceCredit struct {
+	Availability    float64
+	ChargedDowntime int64
+	// Tier is the applied tier name, empty when no tier is applied.
+	Tier string
+	// Hours is the charged downtime hours used by PenaltyPerHour.
+	Hours float64
+	// CreditRatio is the credited ratio of the monthly fee.
+	CreditRatio float64
+	MonthlyFee  float64
+	Amount      float64
+}
+
+// Calculate returns the service credit of an availability and its charged downtime in seconds.
+func (s PenaltySchedule) Calculate(availability float64, chargedDowntime int64, monthlyFee float64) (*ServiceCredit, error) {
+	if monthlyFee < 0 {
+		return nil, fmt.Errorf("monthly fee should not be less than 0: %v", monthlyFee)
+	}
+	credit := ServiceCredit{
+		Availability:    availability,
+		ChargedDowntime: chargedDowntime,
+		MonthlyFee:      monthlyFee,
+	}
+	switch s.Kind {
+	case PenaltyPercentage:
+		for _, tier := range s.Tiers {
+			if availability >= tier.MinAvailability && availability < tier.MaxAvailability {
+				credit.Tier = tier.Name
+				credit.CreditRatio = tier.Credit
+				break
+			}
+		}
+	case PenaltyPerHour:
+		credit.Hours = float64(chargedDowntime) / 3600
+		if s.RoundUpHours {
+			credit.Hours = math.Ceil(credit.Hours)
+		}
+		credit.CreditRatio = credit.Hours * s.HourlyCredit
+	default:
+		return nil, fmt.Errorf("unknown penalty kind: %v", s.Kind)
+	}
+	if s.MaxCredit > 0 && credit.CreditRatio > s.MaxCredit {
+		credit.CreditRatio = s.MaxCredit
+	}
+	credit.Amount = credit.CreditRatio * monthlyFee
+	return &credit, nil
+}
+
+// CalculateForContract returns the service credit of a contract evaluation.
+func (s PenaltySchedule) CalculateForContract(evaluation *ContractEvaluation, monthlyFee float64) (*ServiceCredit, error) {
+	return s.Calculate(evaluation.AdjustedAvailability, evaluation.ChargedDowntime, monthlyFee)
+}
+
+// bakti1ChargedDowntime sums the restitution duration of the link failures and the duration of
+// the open intervals, as the BAKTI availability does.
+func bakti1ChargedDowntime(chronologies []Bakti1UptimeChronology) int64 {
+	var chargedDowntime int64
+	for _, chronology := range chronologies {
+		if chronology.Status == BaktiLinkFailure {
+			chargedDowntime += chronology.RestitutionDuration
+		} else if chronology.Status == BaktiOpen {
+			chargedDowntime += chronology.EndTimestamps - chronology.StartTimestamps
+		}
+	}
+	return chargedDowntime
+}
+
+// baktiSqfChargedDowntime sums the restitution duration of the link failures which are not covered
+// by the SQF rules and the duration of the open intervals, as CalcBaktiSqf does.
+func baktiSqfChargedDowntime(chronologies []BaktiSqfChronology) int64 {
+	var chargedDowntime int64
+	for _, chronology := range chronologies {
+		if chronology.Status == BaktiLinkFailure {
+			if chronology.SqfStatus == Sqfbt713NonQuota || chronology.SqfStatus == Sqflt3 || chronology.SqfStatus == Sqfbt713Quota {
+				chargedDowntime += chronology.RestitutionDuration
+			}
+		} else if chronology.Status == BaktiOpen {
+			chargedDowntime += chronology.EndTimestamps - chronology.StartTimestamps
+		}
+	}
+	return chargedDowntime
+}
+
+// CalculateForBakti1 returns the service credit of a BAKTI availability, the charged downtime
+// is the restitution and open duration of its chronology.
+func (s PenaltySchedule) CalculateForBakti1(availability *Bakti1Availability, monthlyFee float64) (*ServiceCredit, error) {
+	if len(availability.Chronologies) <= 0 {
+		return nil, fmt.Errorf("bakti availability has no chronology")
+	}
+	return s.Calculate(availability.Availability, bakti1ChargedDowntime(availability.Chronologies), monthlyFee)
+}
+
+// CalculateForBaktiSqf returns the service credit of a BAKTI availability considering SQF data.
+func (s PenaltySchedule) CalculateForBaktiSqf(availability *BaktiSqfAvailability, monthlyFee float64) (*ServiceCredit, error) {
+	if len(availability.Chronologies) <= 0 {
+		return nil, fmt.Errorf("bakti sqf availability has no chronology")
+	}
+	return s.Calculate(availability.Availability, baktiSqfChargedDowntime(availability.Chronologies), monthlyFee)
+}
@@ -0,0 +1,125 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestPenaltySchedule(t *testing.T) {
+	tiered := slacalc.PenaltySchedule{
+		Kind: slacalc.PenaltyPercentage,
+		Tiers: []slacalc.PenaltyTier{
+			{Name: "99.0-99.5", MinAvailability: 0.99, MaxAvailability: 0.995, Credit: 0.1},
+			{Name: "95-99", MinAvailability: 0.95, MaxAvailability: 0.99, Credit: 0.25},
+			{Name: "<95", MinAvailability: 0, MaxAvailability: 0.95, Credit: 0.5},
+		},
+	}
+	t.Run("Percentage", func(t *testing.T) {
+		cases := []struct {
+			availability float64
+			tier         string
+			amount       float64
+		}{
+			{0.999, "", 0},
+			{0.992, "99.0-99.5", 100},
+			{0.96, "95-99", 250},
+			{0.5, "<95", 500},
+		}
+		for _, c := range cases {
+			credit, err := tiered.Calculate(c.availability, 0, 1000)
+			if err != nil {
+				t.Fatalf("An Error should not be accoured: %v", err)
+			}
+			if credit.Tier != c.tier || math.Abs(credit.Amount-c.amount) >= ACCURACY {
+				t.Errorf("The credit of %v is %v (%v) instead of %v (%v)", c.availability, credit.Amount, credit.Tier, c.amount, c.tier)
+			}
+		}
+	})
+	t.Run("Per Hour", func(t *testing.T) {
+		hourly := slacalc.PenaltySchedule{
+			Kind:         slacalc.PenaltyPerHour,
+			HourlyCredit: 0.01,
+			RoundUpHours: true,
+			MaxCredit:    0.2,
+		}
+		credit, err := hourly.Calculate(0.99, 3*3600+1, 1000)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if credit.Hours != 4 || math.Abs(credit.Amount-40) >= ACCURACY {
+			t.Errorf("The credit is %v for %v hours instead of 40 for 4 hours", credit.Amount, credit.Hours)
+		}
+		credit, err = hourly.Calculate(0.5, 100*3600, 1000)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if math.Abs(credit.Amount-200) >= ACCURACY {
+			t.Errorf("The capped credit is %v instead of 200", credit.Amount)
+		}
+	})
+	t.Run("Bakti", func(t *testing.T) {
+		uptimeVals := []int{}
+		timestamps := []int64{}
+		exceptions := []bool{}
+		for _, val := range uptimeBaktiSeriesData {
+			uptimeVals = append(uptimeVals, val.Value)
+			timestamps = append(timestamps, val.Timestamp)
+			exceptions = append(exceptions, val.Exception)
+		}
+		calc, err := slacalc.NewUptimeSLACalculator(startTimeBakti, endTimeBakti, timestamps, uptimeVals, toleranceDeltaRatio, exceptions)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		hourly := slacalc.PenaltySchedule{Kind: slacalc.PenaltyPerHour, HourlyCredit: 0.01}
+		credit, err := hourly.CalculateForBakti1(calc.CalcBakti1Uptime(), 1000)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if credit.ChargedDowntime != 800 {
+			t.Errorf("The charged downtime is %v instead of 800", credit.ChargedDowntime)
+		}
+		credit, err = tiered.CalculateForBakti1(calc.CalcBakti1Uptime(), 1000)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if credit.Tier != "<95" {
+			t.Errorf("The applied tier is %v instead of <95", credit.Tier)
+		}
+		// The tolerated restitution of a trimmed availability is charged as is
+		trimmed := calc.CalcBakti1UptimeTrimmed(startTimeBakti, endTimeBakti, true)
+		credit, err = hourly.CalculateForBakti1(trimmed, 1000)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if credit.ChargedDowntime != trimmed.RestitutionDuration+trimmed.OpenDuration {
+			t.Errorf("The charged downtime is %v instead of %v", credit.ChargedDowntime, trimmed.RestitutionDuration+trimmed.OpenDuration)
+		}
+		sqfTimestamps := []int64{}
+		sqfValues := []float64{}
+		for _, chronology := range trimmed.Chronologies {
+			sqfTimestamps = append(sqfTimestamps, chronology.StartTimestamps)
+			sqfValues = append(sqfValues, 5)
+		}
+		sqf, _, err := slacalc.CalcBaktiSqf(trimmed.Chronologies, sqfTimestamps, sqfValues, 100)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		credit, err = hourly.CalculateForBaktiSqf(sqf, 1000)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if credit.ChargedDowntime != sqf.RestitutionDuration+sqf.OpenDuration {
+			t.Errorf("The charged downtime is %v instead of %v", credit.ChargedDowntime, sqf.RestitutionDuration+sqf.OpenDuration)
+		}
+	})
+	t.Run("Invalid Schedule", func(t *testing.T) {
+		if _, err := (slacalc.PenaltySchedule{Kind: -1}).Calculate(0.9, 0, 1000); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := tiered.Calculate(0.9, 0, -1); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
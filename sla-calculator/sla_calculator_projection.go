@@ -0,0 +1,87 @@
+package slacalculator
+
+import (
+	"fmt"
+	"math"
+)
+
+// PROJECTION_EPSILON is the float error tolerated on computing the required uptime of the target.
+const PROJECTION_EPSILON = 1e-6
+
+// FormulaProjection is the projected availability of a formula at the end time.
+type FormulaProjection struct {
+	Formula string
+	// Observed is the availability from the start time until the last timestamp.
+	Observed float64
+	// BestCase assumes the remaining time is up, while WorstCase assumes it is down.
+	BestCase  float64
+	WorstCase float64
+	// Expected assumes the remaining time follows the observed availability.
+	Expected float64
+	// MaxFurtherDowntime is the downtime allowed in the remaining time to meet the target,
+	// it is negative when the target can not be met anymore.
+	MaxFurtherDowntime int64
+	Attainable         bool
+}
+
+// AvailabilityProjection explains the projected availability of the period at the end time.
+type AvailabilityProjection struct {
+	Target            float64
+	ObservedUntil     int64
+	EndTime           int64
+	RemainingDuration int64
+	Formulas          []FormulaProjection
+}
+
+// ProjectAvailability projects the availability of each formula at the end time, based on the
+// series observed until the last timestamp. Every generic formula is projected when no formula is given.
+func (u *UptimeSLACalculator) ProjectAvailability(target float64, formulas ...string) (*AvailabilityProjection, error) {
+	if target < 0 || target > 1 {
+		return nil, fmt.Errorf("target should be between 0 and 1: %v", target)
+	}
+	if len(formulas) <= 0 {
+		formulas = u.availableFormulas()
+	}
+	observedUntil := u.timestamps[len(u.timestamps)-1]
+	projection := AvailabilityProjection{
+		Target:            target,
+		ObservedUntil:     observedUntil,
+		EndTime:           u.endTime,
+		RemainingDuration: u.endTime - observedUntil,
+	}
+	periodDuration := float64(u.endTime - u.startTime)
+	observedDuration := observedUntil - u.startTime
+	// The product is rounded to the epsilon before Ceil, so the float error of ie: 0.81*10000
+	// does not require an extra second.
+	requiredUptime := int64(math.Ceil(target*periodDuration - PROJECTION_EPSILON))
+	for _, formula := range formulas {
+		intervals, err := u.countedIntervals(formula)
+		if err != nil {
+			return nil, err
+		}
+		var observedUptime int64
+		for _, interval := range intervals {
+			if interval.EndTime <= observedUntil {
+				observedUptime += interval.Counted
+			}
+		}
+		observed := 1.0
+		if observedDuration > 0 {
+			observed = float64(observedUptime) / float64(observedDuration)
+		}
+		formulaProjection := FormulaProjection{
+			Formula:            formula,
+			Observed:           observed,
+			BestCase:           float64(observedUptime+projection.RemainingDuration) / periodDuration,
+			WorstCase:          float64(observedUptime) / periodDuration,
+			Expected:           (float64(observedUptime) + float64(projection.RemainingDuration)*observed) / periodDuration,
+			MaxFurtherDowntime: observedUptime + projection.RemainingDuration - requiredUptime,
+		}
+		if formulaProjection.MaxFurtherDowntime > projection.RemainingDuration {
+			formulaProjection.MaxFurtherDowntime = projection.RemainingDuration
+		}
+		formulaProjection.Attainable = formulaProjection.MaxFurtherDowntime >= 0
+		projection.Formulas = append(projection.Formulas, formulaProjection)
+	}
+	return &projection, nil
+}
@@ -0,0 +1,78 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestProjectAvailability(t *testing.T) {
+	// Observed until 1000 out of 2000, with 100 seconds downtime
+	timestamps := []int64{100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}
+	uptimeVals := []int{100, 200, 300, 400, 0, 100, 200, 300, 400, 500}
+	calc, err := slacalc.NewUptimeSLACalculator(0, 2000, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	projection, err := calc.ProjectAvailability(0.9)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	if projection.RemainingDuration != 1000 || projection.ObservedUntil != 1000 {
+		t.Errorf("The remaining duration is %v instead of 1000", projection.RemainingDuration)
+	}
+	if len(projection.Formulas) != 3 {
+		t.Fatalf("The amount of projected formulas is %v instead of 3", len(projection.Formulas))
+	}
+	for _, formulaProjection := range projection.Formulas {
+		if formulaProjection.Formula != slacalc.FORMULA_SNMP {
+			continue
+		}
+		if math.Abs(formulaProjection.Observed-0.9) >= ACCURACY {
+			t.Errorf("The observed availability is %v instead of 0.9", formulaProjection.Observed)
+		}
+		if math.Abs(formulaProjection.BestCase-0.95) >= ACCURACY {
+			t.Errorf("The best case availability is %v instead of 0.95", formulaProjection.BestCase)
+		}
+		if math.Abs(formulaProjection.WorstCase-0.45) >= ACCURACY {
+			t.Errorf("The worst case availability is %v instead of 0.45", formulaProjection.WorstCase)
+		}
+		if math.Abs(formulaProjection.Expected-0.9) >= ACCURACY {
+			t.Errorf("The expected availability is %v instead of 0.9", formulaProjection.Expected)
+		}
+		if formulaProjection.MaxFurtherDowntime != 100 || !formulaProjection.Attainable {
+			t.Errorf("The max further downtime is %v instead of 100", formulaProjection.MaxFurtherDowntime)
+		}
+	}
+	t.Run("Unattainable Target", func(t *testing.T) {
+		projection, err := calc.ProjectAvailability(0.99, slacalc.FORMULA_SNMP)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if projection.Formulas[0].Attainable || projection.Formulas[0].MaxFurtherDowntime != -80 {
+			t.Errorf("The target should be unattainable by 80 seconds: %+v", projection.Formulas[0])
+		}
+	})
+	t.Run("Target Boundary", func(t *testing.T) {
+		calc, err := slacalc.NewUptimeSLACalculator(0, 10000, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		// 0.81*10000 is 8100.000000000001 in float, but 8100 seconds of uptime meet the target
+		for target, expected := range map[float64]int64{0.81: 1800, 0.99: 0} {
+			projection, err := calc.ProjectAvailability(target, slacalc.FORMULA_SNMP)
+			if err != nil {
+				t.Fatalf("An Error should not be accoured: %v", err)
+			}
+			if projection.Formulas[0].MaxFurtherDowntime != expected || !projection.Formulas[0].Attainable {
+				t.Errorf("The max further downtime of target %v is %v instead of %v", target, projection.Formulas[0].MaxFurtherDowntime, expected)
+			}
+		}
+	})
+	t.Run("Invalid Target", func(t *testing.T) {
+		if _, err := calc.ProjectAvailability(1.1); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package slacalculator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusMetricKind tells how the value of a Prometheus sample is turned into uptime.
+type PrometheusMetricKind int
+
+const (
+	// PrometheusUptime is an uptime counter, ie: sysUpTime of snmp_exporter.
+	PrometheusUptime PrometheusMetricKind = iota
+	// PrometheusBootTime is a boot time gauge in unix seconds, ie: node_boot_time_seconds.
+	PrometheusBootTime
+)
+
+// PrometheusOptions configures the Prometheus range query importer.
+type PrometheusOptions struct {
+	Kind PrometheusMetricKind
+	// KeyLabel keys the series by the value of the label, ie: instance. Empty means
+	// the whole label set, ie: sysUpTime{instance="r1"}.
+	KeyLabel string
+	// Step is the query resolution in seconds, zero means the smallest gap of every series.
+	Step int64
+	// ValueScale multiplies the sample values, ie: 0.01 for timeticks, zero means 1.
+	ValueScale float64
+}
+
+type prometheusResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string             `json:"resultType"`
+		Result     []prometheusSeries `json:"result"`
+	} `json:"data"`
+}
+
+type prometheusSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values [][2]interface{}  `json:"values"`
+}
+
+func prometheusSeriesKey(metric map[string]string, keyLabel string) string {
+	if keyLabel != "" {
+		return metric[keyLabel]
+	}
+	names := []string{}
+	for name := range metric {
+		if name != "__name__" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	labels := []string{}
+	for _, name := range names {
+		labels = append(labels, fmt.Sprintf("%v=%q", name, metric[name]))
+	}
+	return metric["__name__"] + "{" + strings.Join(labels, ",") + "}"
+}
+
+func parsePrometheusSample(value [2]interface{}) (int64, float64, error) {
+	timestamp, ok := value[0].(float64)
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid sample timestamp: %v", value[0])
+	}
+	text, ok := value[1].(string)
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid sample value: %v", value[1])
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid sample value: %v", text)
+	}
+	return int64(timestamp), number, nil
+}
+
+// ReadPrometheusMatrix reads a Prometheus query_range JSON response of matrix type into series keyed
+// by the options. Prometheus leaves out the steps on which a series is stale, so every missing step
+// is imported as a zero uptime sample. A boot time gauge is turned into the uptime at the sample
+// timestamp. Non finite values are imported as zero uptime samples.
+func ReadPrometheusMatrix(r io.Reader, options PrometheusOptions) (map[string]UptimeSeries, error) {
+	if options.Kind != PrometheusUptime && options.Kind != PrometheusBootTime {
+		return nil, fmt.Errorf("unknown prometheus metric kind: %v", options.Kind)
+	}
+	if options.Step < 0 {
+		return nil, fmt.Errorf("step should not be less than 0: %v", options.Step)
+	}
+	response := prometheusResponse{}
+	if err := json.NewDecoder(r).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed on decoding prometheus response: %v", err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %v: %v", response.ErrorType, response.Error)
+	}
+	if response.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("prometheus result type should be matrix: %v", response.Data.ResultType)
+	}
+	scale := options.ValueScale
+	if scale == 0 {
+		scale = 1
+	}
+	seriesMap := map[string]UptimeSeries{}
+	for _, result := range response.Data.Result {
+		key := prometheusSeriesKey(result.Metric, options.KeyLabel)
+		if _, ok := seriesMap[key]; ok {
+			return nil, fmt.Errorf("duplicate prometheus series key: %v", key)
+		}
+		timestamps := []int64{}
+		values := []float64{}
+		for _, value := range result.Values {
+			timestamp, number, err := parsePrometheusSample(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed on parsing %v: %v", key, err)
+			}
+			timestamps = append(timestamps, timestamp)
+			values = append(values, number)
+		}
+		step := options.Step
+		if step == 0 {
+			for i := 1; i < len(timestamps); i++ {
+				gap := timestamps[i] - timestamps[i-1]
+				if gap > 0 && (step == 0 || gap < step) {
+					step = gap
+				}
+			}
+		}
+		series := UptimeSeries{}
+		for i, timestamp := range timestamps {
+			// Fill the staleness gap with zero samples on the missing steps
+			if i > 0 && step > 0 {
+				for missing := timestamps[i-1] + step; missing < timestamp; missing += step {
+					series.Timestamps = append(series.Timestamps, missing)
+					series.UptimeValues = append(series.UptimeValues, 0)
+				}
+			}
+			uptime := 0
+			if !math.IsNaN(values[i]) && !math.IsInf(values[i], 0) {
+				value := values[i] * scale
+				if options.Kind == PrometheusBootTime {
+					value = float64(timestamp) - value
+				}
+				uptime = int(math.Round(value))
+				if uptime < 0 {
+					uptime = 0
+				}
+			}
+			series.Timestamps = append(series.Timestamps, timestamp)
+			series.UptimeValues = append(series.UptimeValues, uptime)
+		}
+		seriesMap[key] = series
+	}
+	return seriesMap, nil
+}
+
+// QueryPrometheusRange runs the range query against the Prometheus HTTP API at the base URL,
+// ie: http://localhost:9090, and reads the response by ReadPrometheusMatrix.
+func QueryPrometheusRange(ctx context.Context, client *http.Client, baseURL, query string, start, end, step int64, options PrometheusOptions) (map[string]UptimeSeries, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step should be greater than 0: %v", step)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(start, 10))
+	params.Set("end", strconv.FormatInt(end, 10))
+	params.Set("step", strconv.FormatInt(step, 10))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed on creating prometheus request: %v", err)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed on querying prometheus: %v", err)
+	}
+	defer response.Body.Close()
+	// Prometheus explains a failed query in the body, which is read along with the successful one
+	if response.StatusCode != http.StatusOK && !strings.HasPrefix(response.Header.Get("Content-Type"), "application/json") {
+		return nil, fmt.Errorf("prometheus responded with status %v", response.Status)
+	}
+	if options.Step == 0 {
+		options.Step = step
+	}
+	return ReadPrometheusMatrix(response.Body, options)
+}
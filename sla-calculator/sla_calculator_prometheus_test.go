@@ -0,0 +1,107 @@
+package slacalculator_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func readPrometheusFixture(t *testing.T, name string, options slacalc.PrometheusOptions) map[string]slacalc.UptimeSeries {
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	defer file.Close()
+	seriesMap, err := slacalc.ReadPrometheusMatrix(file, options)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	return seriesMap
+}
+
+func checkPrometheusSeries(t *testing.T, series slacalc.UptimeSeries, expectedValues []int) {
+	if len(series.UptimeValues) != len(expectedValues) {
+		t.Fatalf("The amount of samples is %v instead of %v", len(series.UptimeValues), len(expectedValues))
+	}
+	if series.Exceptions != nil {
+		t.Errorf("The exceptions should be nil as Prometheus has no exception data")
+	}
+	for i := range expectedValues {
+		expectedTimestamp := 1700000000 + int64(i)*60
+		if series.Timestamps[i] != expectedTimestamp {
+			t.Errorf("The timestamp %v is %v instead of %v", i, series.Timestamps[i], expectedTimestamp)
+		}
+		if series.UptimeValues[i] != expectedValues[i] {
+			t.Errorf("The uptime value %v is %v instead of %v", i, series.UptimeValues[i], expectedValues[i])
+		}
+	}
+}
+
+func TestReadPrometheusMatrix(t *testing.T) {
+	t.Run("Uptime With Staleness Gap", func(t *testing.T) {
+		seriesMap := readPrometheusFixture(t, "prometheus_sysuptime.json", slacalc.PrometheusOptions{KeyLabel: "instance", ValueScale: 0.01})
+		if len(seriesMap) != 2 {
+			t.Fatalf("The amount of series is %v instead of 2", len(seriesMap))
+		}
+		checkPrometheusSeries(t, seriesMap["r1"], []int{1000, 1060, 0, 0, 1240})
+		checkPrometheusSeries(t, seriesMap["r2"], []int{5, 65, 125, 185, 245})
+	})
+	t.Run("Label Set Key", func(t *testing.T) {
+		seriesMap := readPrometheusFixture(t, "prometheus_sysuptime.json", slacalc.PrometheusOptions{Step: 60})
+		if _, ok := seriesMap[`sysUpTime{instance="r1",job="snmp"}`]; !ok {
+			t.Errorf("The series should be keyed by its label set: %v", seriesMap)
+		}
+	})
+	t.Run("Boot Time", func(t *testing.T) {
+		seriesMap := readPrometheusFixture(t, "prometheus_boot_time.json", slacalc.PrometheusOptions{Kind: slacalc.PrometheusBootTime, KeyLabel: "instance"})
+		checkPrometheusSeries(t, seriesMap["r3:9100"], []int{1000, 1060, 1120, 30, 0})
+	})
+	t.Run("Error Response", func(t *testing.T) {
+		_, err := slacalc.ReadPrometheusMatrix(strings.NewReader(`{"status":"error","errorType":"bad_data","error":"parse error"}`), slacalc.PrometheusOptions{})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+	t.Run("Vector Response", func(t *testing.T) {
+		_, err := slacalc.ReadPrometheusMatrix(strings.NewReader(`{"status":"success","data":{"resultType":"vector","result":[]}}`), slacalc.PrometheusOptions{})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
+
+func TestQueryPrometheusRange(t *testing.T) {
+	fixture, err := ioutil.ReadFile("testdata/prometheus_sysuptime.json")
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/v1/query_range" || query.Get("query") != "sysUpTime" || query.Get("step") != "60" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+	defer server.Close()
+	t.Run("Success", func(t *testing.T) {
+		seriesMap, err := slacalc.QueryPrometheusRange(context.Background(), server.Client(), server.URL, "sysUpTime", 1700000000, 1700000240, 60, slacalc.PrometheusOptions{KeyLabel: "instance", ValueScale: 0.01})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		checkPrometheusSeries(t, seriesMap["r1"], []int{1000, 1060, 0, 0, 1240})
+	})
+	t.Run("Bad Request", func(t *testing.T) {
+		_, err := slacalc.QueryPrometheusRange(context.Background(), server.Client(), server.URL, "up", 1700000000, 1700000240, 60, slacalc.PrometheusOptions{})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,331 @@
+package slacalculator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// PRTG_FORMAT_XML is the historicdata.xml export format.
+	PRTG_FORMAT_XML = "xml"
+	// PRTG_FORMAT_JSON is the historicdata.json export format.
+	PRTG_FORMAT_JSON = "json"
+	// PRTG_FORMAT_CSV is the historicdata.csv export format.
+	PRTG_FORMAT_CSV = "csv"
+
+	// PRTG_UPTIME_CHANNEL is the default uptime channel of the SNMP system uptime sensor.
+	PRTG_UPTIME_CHANNEL = "System Uptime"
+	// PRTG_DOWNTIME_CHANNEL is the default downtime channel, in percent of the averaging interval.
+	PRTG_DOWNTIME_CHANNEL = "Downtime"
+
+	// prtgOLEEpochDays is the unix epoch in OLE automation days, which PRTG uses for datetime_raw.
+	prtgOLEEpochDays = 25569
+	// prtgFullCoverage is the raw coverage of a fully covered averaging interval, ie: 100.00%.
+	prtgFullCoverage = 10000
+)
+
+// PRTGOptions configures the PRTG historic data importer.
+type PRTGOptions struct {
+	// UptimeChannel is the channel of the uptime in seconds, empty means PRTG_UPTIME_CHANNEL.
+	UptimeChannel string
+	// DowntimeChannel is the channel of the downtime percentage, empty means PRTG_DOWNTIME_CHANNEL.
+	DowntimeChannel string
+	// AveragingInterval is the length of an item in seconds, zero means the smallest gap between items.
+	AveragingInterval int64
+	// TimestampAtStart places a sample at the start of its averaging interval instead of its end.
+	TimestampAtStart bool
+	// MinCoverage is the minimum coverage ratio of an item to carry data, ie: 0.5. Zero means
+	// any coverage greater than zero.
+	MinCoverage float64
+	// SkipNoData drops the no data items instead of importing them as zero uptime samples.
+	SkipNoData bool
+	// UptimeScale multiplies the uptime values, ie: 0.01 for timeticks, zero means 1.
+	UptimeScale float64
+	// Location is the time zone of datetime_raw, nil means UTC.
+	Location *time.Location
+}
+
+// PRTGReport explains how the items of a PRTG historic data export are imported.
+type PRTGReport struct {
+	Items             int
+	AveragingInterval int64
+	// NoData lists the end of every item without uptime value or with too little coverage.
+	NoData []int64
+	// Down lists the end of every item which PRTG marks as fully down.
+	Down []int64
+}
+
+// prtgItem is an item of an export before the averaging interval is applied. Empty strings
+// mean the export has no value.
+type prtgItem struct {
+	datetime string
+	uptime   string
+	downtime string
+	coverage string
+}
+
+type prtgXMLHistData struct {
+	Items []prtgXMLItem `xml:"item"`
+}
+
+type prtgXMLItem struct {
+	DatetimeRaw string         `xml:"datetime_raw"`
+	Values      []prtgXMLValue `xml:"value_raw"`
+	CoverageRaw string         `xml:"coverage_raw"`
+}
+
+type prtgXMLValue struct {
+	Channel string `xml:"channel,attr"`
+	Value   string `xml:",chardata"`
+}
+
+func (o PRTGOptions) uptimeChannel() string {
+	if o.UptimeChannel == "" {
+		return PRTG_UPTIME_CHANNEL
+	}
+	return o.UptimeChannel
+}
+
+func (o PRTGOptions) downtimeChannel() string {
+	if o.DowntimeChannel == "" {
+		return PRTG_DOWNTIME_CHANNEL
+	}
+	return o.DowntimeChannel
+}
+
+// ReadPRTGHistoricData reads a PRTG historicdata export of an uptime sensor in the given format
+// into an uptime series. PRTG reports the average uptime of every averaging interval, which is the
+// uptime at the middle of the interval, so half of the interval is added to the sample at the end
+// of the interval, or subtracted from the sample at its start. No data items and items marked as
+// fully down are imported as zero uptime samples.
+func ReadPRTGHistoricData(r io.Reader, format string, options PRTGOptions) (UptimeSeries, PRTGReport, error) {
+	var items []prtgItem
+	var err error
+	switch format {
+	case PRTG_FORMAT_XML:
+		items, err = readPRTGXMLItems(r, options)
+	case PRTG_FORMAT_JSON:
+		items, err = readPRTGJSONItems(r, options)
+	case PRTG_FORMAT_CSV:
+		items, err = readPRTGCSVItems(r, options)
+	default:
+		err = fmt.Errorf("unknown prtg format: %v", format)
+	}
+	if err != nil {
+		return UptimeSeries{}, PRTGReport{}, err
+	}
+	return importPRTGItems(items, options)
+}
+
+func readPRTGXMLItems(r io.Reader, options PRTGOptions) ([]prtgItem, error) {
+	histData := prtgXMLHistData{}
+	if err := xml.NewDecoder(r).Decode(&histData); err != nil {
+		return nil, fmt.Errorf("failed on decoding prtg xml: %v", err)
+	}
+	items := []prtgItem{}
+	for _, xmlItem := range histData.Items {
+		item := prtgItem{
+			datetime: xmlItem.DatetimeRaw,
+			coverage: xmlItem.CoverageRaw,
+		}
+		for _, value := range xmlItem.Values {
+			switch value.Channel {
+			case options.uptimeChannel():
+				item.uptime = value.Value
+			case options.downtimeChannel():
+				item.downtime = value.Value
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func prtgJSONString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func readPRTGJSONItems(r io.Reader, options PRTGOptions) ([]prtgItem, error) {
+	export := struct {
+		HistData []map[string]interface{} `json:"histdata"`
+	}{}
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed on decoding prtg json: %v", err)
+	}
+	items := []prtgItem{}
+	for _, jsonItem := range export.HistData {
+		items = append(items, prtgItem{
+			datetime: prtgJSONString(jsonItem["datetime_raw"]),
+			uptime:   prtgJSONString(jsonItem[options.uptimeChannel()+"(RAW)"]),
+			downtime: prtgJSONString(jsonItem[options.downtimeChannel()+"(RAW)"]),
+			coverage: prtgJSONString(jsonItem["coverage_raw"]),
+		})
+	}
+	return items, nil
+}
+
+func readPRTGCSVItems(r io.Reader, options PRTGOptions) ([]prtgItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed on reading prtg csv header: %v", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	datetimeColumn, ok := columns["Date Time(RAW)"]
+	if !ok {
+		return nil, fmt.Errorf("prtg csv has no Date Time(RAW) column")
+	}
+	uptimeColumn, ok := columns[options.uptimeChannel()+"(RAW)"]
+	if !ok {
+		return nil, fmt.Errorf("prtg csv has no %v(RAW) column", options.uptimeChannel())
+	}
+	field := func(record []string, name string) string {
+		column, ok := columns[name]
+		if !ok || column >= len(record) {
+			return ""
+		}
+		return record[column]
+	}
+	items := []prtgItem{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed on reading prtg csv: %v", err)
+		}
+		// The trailing averages row has no raw datetime
+		if datetimeColumn >= len(record) || strings.TrimSpace(record[datetimeColumn]) == "" {
+			continue
+		}
+		item := prtgItem{
+			datetime: record[datetimeColumn],
+			downtime: field(record, options.downtimeChannel()+"(RAW)"),
+			coverage: field(record, "Coverage(RAW)"),
+		}
+		if uptimeColumn < len(record) {
+			item.uptime = record[uptimeColumn]
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func prtgOLEToUnix(days float64, location *time.Location) int64 {
+	timestamp := int64(math.Round((days - prtgOLEEpochDays) * 86400))
+	if location == nil {
+		return timestamp
+	}
+	wallClock := time.Unix(timestamp, 0).UTC()
+	return time.Date(wallClock.Year(), wallClock.Month(), wallClock.Day(),
+		wallClock.Hour(), wallClock.Minute(), wallClock.Second(), 0, location).Unix()
+}
+
+func parsePRTGFloat(value string) (float64, bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	return number, err == nil, err
+}
+
+func importPRTGItems(items []prtgItem, options PRTGOptions) (UptimeSeries, PRTGReport, error) {
+	report := PRTGReport{Items: len(items)}
+	if options.AveragingInterval < 0 {
+		return UptimeSeries{}, report, fmt.Errorf("averaging interval should not be less than 0: %v", options.AveragingInterval)
+	}
+	ends := []int64{}
+	for i, item := range items {
+		days, ok, err := parsePRTGFloat(item.datetime)
+		if err != nil || !ok {
+			return UptimeSeries{}, report, fmt.Errorf("failed on parsing datetime_raw of item %v: %q", i, item.datetime)
+		}
+		ends = append(ends, prtgOLEToUnix(days, options.Location))
+	}
+	interval := options.AveragingInterval
+	if interval == 0 {
+		sorted := append([]int64{}, ends...)
+		sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+		for i := 1; i < len(sorted); i++ {
+			gap := sorted[i] - sorted[i-1]
+			if gap > 0 && (interval == 0 || gap < interval) {
+				interval = gap
+			}
+		}
+		if interval == 0 && len(items) > 0 {
+			return UptimeSeries{}, report, fmt.Errorf("averaging interval could not be inferred")
+		}
+	}
+	report.AveragingInterval = interval
+	scale := options.UptimeScale
+	if scale == 0 {
+		scale = 1
+	}
+	series := UptimeSeries{}
+	for i, item := range items {
+		end := ends[i]
+		uptime, hasUptime, err := parsePRTGFloat(item.uptime)
+		if err != nil {
+			return UptimeSeries{}, report, fmt.Errorf("failed on parsing uptime of item %v: %v", i, err)
+		}
+		downtime, _, err := parsePRTGFloat(item.downtime)
+		if err != nil {
+			return UptimeSeries{}, report, fmt.Errorf("failed on parsing downtime of item %v: %v", i, err)
+		}
+		coverage, hasCoverage, err := parsePRTGFloat(item.coverage)
+		if err != nil {
+			return UptimeSeries{}, report, fmt.Errorf("failed on parsing coverage of item %v: %v", i, err)
+		}
+		noData := !hasUptime
+		if hasCoverage && (coverage <= 0 || coverage/prtgFullCoverage < options.MinCoverage) {
+			noData = true
+		}
+		timestamp := end
+		if options.TimestampAtStart {
+			timestamp = end - interval
+		}
+		value := 0
+		switch {
+		case noData:
+			report.NoData = append(report.NoData, end)
+			if options.SkipNoData {
+				continue
+			}
+		case downtime >= 100:
+			report.Down = append(report.Down, end)
+		default:
+			// The average uptime is the uptime at the middle of the averaging interval
+			shifted := uptime*scale + float64(interval)/2
+			if options.TimestampAtStart {
+				shifted = uptime*scale - float64(interval)/2
+			}
+			value = int(math.Round(shifted))
+			if value < 1 {
+				value = 1
+			}
+		}
+		series.Timestamps = append(series.Timestamps, timestamp)
+		series.UptimeValues = append(series.UptimeValues, value)
+	}
+	return series, report, nil
+}
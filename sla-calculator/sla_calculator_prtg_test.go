@@ -0,0 +1,87 @@
+package slacalculator_test
+
+import (
+	"os"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+var prtgStartTime int64 = 1700006400
+
+func readPRTGFixture(t *testing.T, format string, options slacalc.PRTGOptions) (slacalc.UptimeSeries, slacalc.PRTGReport) {
+	file, err := os.Open("testdata/prtg_historicdata." + format)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	defer file.Close()
+	series, report, err := slacalc.ReadPRTGHistoricData(file, format, options)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	return series, report
+}
+
+func TestReadPRTGHistoricData(t *testing.T) {
+	for _, format := range []string{slacalc.PRTG_FORMAT_XML, slacalc.PRTG_FORMAT_JSON, slacalc.PRTG_FORMAT_CSV} {
+		t.Run(format, func(t *testing.T) {
+			series, report := readPRTGFixture(t, format, slacalc.PRTGOptions{})
+			if report.Items != 6 {
+				t.Errorf("The amount of items is %v instead of 6", report.Items)
+			}
+			if report.AveragingInterval != 300 {
+				t.Errorf("The averaging interval is %v instead of 300", report.AveragingInterval)
+			}
+			if len(report.NoData) != 1 || report.NoData[0] != prtgStartTime+900 {
+				t.Errorf("The no data items are %v instead of [%v]", report.NoData, prtgStartTime+900)
+			}
+			if len(report.Down) != 1 || report.Down[0] != prtgStartTime+1200 {
+				t.Errorf("The down items are %v instead of [%v]", report.Down, prtgStartTime+1200)
+			}
+			expectedValues := []int{1150, 1450, 0, 0, 250, 550}
+			if len(series.UptimeValues) != len(expectedValues) {
+				t.Fatalf("The amount of samples is %v instead of %v", len(series.UptimeValues), len(expectedValues))
+			}
+			for i := range expectedValues {
+				expectedTimestamp := prtgStartTime + int64(i+1)*300
+				if series.Timestamps[i] != expectedTimestamp {
+					t.Errorf("The timestamp %v is %v instead of %v", i, series.Timestamps[i], expectedTimestamp)
+				}
+				if series.UptimeValues[i] != expectedValues[i] {
+					t.Errorf("The uptime value %v is %v instead of %v", i, series.UptimeValues[i], expectedValues[i])
+				}
+			}
+			if series.Exceptions != nil {
+				t.Errorf("The exceptions should be nil as PRTG has no exception data")
+			}
+			if _, err := series.NewCalculator(prtgStartTime, prtgStartTime+1800, 0.9); err != nil {
+				t.Errorf("An Error should not be accoured: %v", err)
+			}
+		})
+	}
+	t.Run("Timestamp At Start", func(t *testing.T) {
+		series, _ := readPRTGFixture(t, slacalc.PRTG_FORMAT_XML, slacalc.PRTGOptions{TimestampAtStart: true})
+		expectedValues := []int{850, 1150, 0, 0, 1, 250}
+		for i := range expectedValues {
+			expectedTimestamp := prtgStartTime + int64(i)*300
+			if series.Timestamps[i] != expectedTimestamp {
+				t.Errorf("The timestamp %v is %v instead of %v", i, series.Timestamps[i], expectedTimestamp)
+			}
+			if series.UptimeValues[i] != expectedValues[i] {
+				t.Errorf("The uptime value %v is %v instead of %v", i, series.UptimeValues[i], expectedValues[i])
+			}
+		}
+	})
+	t.Run("Skip No Data", func(t *testing.T) {
+		series, _ := readPRTGFixture(t, slacalc.PRTG_FORMAT_JSON, slacalc.PRTGOptions{SkipNoData: true, AveragingInterval: 300})
+		if len(series.Timestamps) != 5 {
+			t.Errorf("The amount of samples is %v instead of 5", len(series.Timestamps))
+		}
+	})
+	t.Run("Unknown Format", func(t *testing.T) {
+		_, _, err := slacalc.ReadPRTGHistoricData(nil, "txt", slacalc.PRTGOptions{})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,308 @@
+package slacalculator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// REPORT_STATE_UP is the timeline state of an up interval.
+	REPORT_STATE_UP = "up"
+	// REPORT_STATE_DOWN is the timeline state of a down interval without known cause.
+	REPORT_STATE_DOWN = "down"
+	// REPORT_STATE_OPEN is the timeline state of an interval without data.
+	REPORT_STATE_OPEN = "open"
+	// REPORT_STATE_LINK_FAILURE is the timeline state of a BAKTI link failure.
+	REPORT_STATE_LINK_FAILURE = "link-failure"
+	// REPORT_STATE_POWER_FAILURE is the timeline state of a BAKTI power failure.
+	REPORT_STATE_POWER_FAILURE = "power-failure"
+	// REPORT_STATE_EXCLUDED is the timeline state of an excluded interval, ie: a planned maintenance.
+	REPORT_STATE_EXCLUDED = "excluded"
+)
+
+// SLAReportOptions describes the device, period and terms of an SLA report.
+type SLAReportOptions struct {
+	Title  string
+	Device string
+	// Contract is the contract name shown in the report header.
+	Contract    string
+	PeriodStart int64
+	PeriodEnd   int64
+	// Target is the availability target of every formula, ie: 0.995 for 99.5%.
+	Target float64
+	// Penalty computes the service credit of every formula, nil means no penalty.
+	Penalty    *PenaltySchedule
+	MonthlyFee float64
+	// Location is the time zone of the report timestamps, nil means UTC.
+	Location *time.Location
+}
+
+// SLAReportLine is the summary of a formula in an SLA report.
+type SLAReportLine struct {
+	Formula      string
+	Availability float64
+	Uptime       int64
+	Downtime     int64
+	Target       float64
+	Compliant    bool
+	// Credit is nil when the report has no penalty schedule.
+	Credit *ServiceCredit
+}
+
+// SLAReportChronology is an interval of the BAKTI chronology of an SLA report.
+type SLAReportChronology struct {
+	StartTime           int64
+	EndTime             int64
+	UptimeValue         int64
+	Status              int
+	StatusName          string
+	LinkFailureDuration int64
+	RestitutionDuration int64
+	// SqfStatusName is empty when the chronology has no SQF data.
+	SqfStatus     int
+	SqfStatusName string
+	SqfValue      float64
+	RainQuota     int64
+}
+
+// Duration returns the length of the interval in seconds.
+func (c SLAReportChronology) Duration() int64 {
+	return c.EndTime - c.StartTime
+}
+
+// SLAReportSegment is an interval of the report timeline.
+type SLAReportSegment struct {
+	StartTime int64
+	EndTime   int64
+	State     string
+}
+
+// Duration returns the length of the segment in seconds.
+func (s SLAReportSegment) Duration() int64 {
+	return s.EndTime - s.StartTime
+}
+
+// SLAReport is the content of an SLA report, independent of its output format.
+type SLAReport struct {
+	SLAReportOptions
+	Lines        []SLAReportLine
+	Chronologies []SLAReportChronology
+	// Timeline is the state of the device over the period, before the exclusions are applied.
+	Timeline   []SLAReportSegment
+	Exclusions []Exclusion
+}
+
+// NewSLAReport returns an empty SLA report of the options.
+func NewSLAReport(options SLAReportOptions) (*SLAReport, error) {
+	if options.PeriodEnd <= options.PeriodStart {
+		return nil, fmt.Errorf("period end should be greater than period start: %v, %v", options.PeriodStart, options.PeriodEnd)
+	}
+	if options.Target < 0 || options.Target > 1 {
+		return nil, fmt.Errorf("target should be between 0 and 1: %v", options.Target)
+	}
+	return &SLAReport{SLAReportOptions: options}, nil
+}
+
+func (r *SLAReport) location() *time.Location {
+	if r.Location == nil {
+		return time.UTC
+	}
+	return r.Location
+}
+
+func (r *SLAReport) addLine(formula string, availability float64, uptime, downtime int64) error {
+	line := SLAReportLine{
+		Formula:      formula,
+		Availability: availability,
+		Uptime:       uptime,
+		Downtime:     downtime,
+		Target:       r.Target,
+		Compliant:    availability >= r.Target,
+	}
+	if r.Penalty != nil {
+		credit, err := r.Penalty.Calculate(availability, downtime, r.MonthlyFee)
+		if err != nil {
+			return err
+		}
+		line.Credit = credit
+	}
+	r.Lines = append(r.Lines, line)
+	return nil
+}
+
+// AddFormulaResult adds the summary of a formula result.
+func (r *SLAReport) AddFormulaResult(result *FormulaResult) error {
+	return r.addLine(result.Formula, result.Availability, result.Uptime, result.Downtime)
+}
+
+// AddBakti1Availability adds the summary of a BAKTI availability and replaces the chronology
+// and the timeline by its chronology.
+func (r *SLAReport) AddBakti1Availability(availability *Bakti1Availability) error {
+	chronologies := []SLAReportChronology{}
+	for _, chronology := range availability.Chronologies {
+		chronologies = append(chronologies, newSLAReportChronology(chronology))
+	}
+	return r.addBaktiLine(FORMULA_BAKTI1, availability.Availability, bakti1ChargedDowntime(availability.Chronologies), chronologies)
+}
+
+// AddBaktiSqfAvailability adds the summary of a BAKTI availability considering SQF data and
+// replaces the chronology and the timeline by its chronology.
+func (r *SLAReport) AddBaktiSqfAvailability(availability *BaktiSqfAvailability) error {
+	chronologies := []SLAReportChronology{}
+	for _, chronology := range availability.Chronologies {
+		reportChronology := newSLAReportChronology(chronology.Bakti1UptimeChronology)
+		reportChronology.SqfStatus = chronology.SqfStatus
+		reportChronology.SqfStatusName = BaktiStatusName(chronology.SqfStatus)
+		reportChronology.SqfValue = chronology.SqfValue
+		reportChronology.RainQuota = chronology.RainQuota
+		chronologies = append(chronologies, reportChronology)
+	}
+	return r.addBaktiLine(FORMULA_BAKTI_SQF, availability.Availability, baktiSqfChargedDowntime(availability.Chronologies), chronologies)
+}
+
+// addBaktiLine adds the summary of a BAKTI availability, whose period is the one of its chronology.
+func (r *SLAReport) addBaktiLine(formula string, availability float64, downtime int64, chronologies []SLAReportChronology) error {
+	var period int64
+	if len(chronologies) > 0 {
+		period = chronologies[len(chronologies)-1].EndTime - chronologies[0].StartTime
+	}
+	if err := r.addLine(formula, availability, period-downtime, downtime); err != nil {
+		return err
+	}
+	r.Chronologies = chronologies
+	r.Timeline = []SLAReportSegment{}
+	for _, chronology := range chronologies {
+		state := REPORT_STATE_DOWN
+		switch chronology.Status {
+		case BaktiRunning:
+			state = REPORT_STATE_UP
+		case BaktiLinkFailure:
+			state = REPORT_STATE_LINK_FAILURE
+		case BaktiPowerFailure:
+			state = REPORT_STATE_POWER_FAILURE
+		case BaktiOpen:
+			state = REPORT_STATE_OPEN
+		}
+		r.Timeline = append(r.Timeline, SLAReportSegment{chronology.StartTime, chronology.EndTime, state})
+	}
+	return nil
+}
+
+// AddStateIntervals replaces the timeline by the state intervals, ie: the result of GetUptimeStateIntervals.
+func (r *SLAReport) AddStateIntervals(intervals []StateInterval) {
+	r.Timeline = []SLAReportSegment{}
+	for _, interval := range intervals {
+		state := REPORT_STATE_DOWN
+		switch interval.State {
+		case STATE_UP:
+			state = REPORT_STATE_UP
+		case STATE_OPEN:
+			state = REPORT_STATE_OPEN
+		}
+		r.Timeline = append(r.Timeline, SLAReportSegment{interval.StartTime, interval.EndTime, state})
+	}
+}
+
+// AddExclusions adds the exclusions which are shown as excluded on the timeline.
+func (r *SLAReport) AddExclusions(exclusions ...Exclusion) {
+	r.Exclusions = append(r.Exclusions, exclusions...)
+}
+
+// Segments returns the timeline clipped to the period, with the exclusions applied and the
+// adjacent segments of the same state merged.
+func (r *SLAReport) Segments() []SLAReportSegment {
+	ranges := [][2]int64{}
+	for _, exclusion := range r.Exclusions {
+		ranges = append(ranges, [2]int64{exclusion.StartTime, exclusion.EndTime})
+	}
+	excluded := mergeRanges(ranges)
+	segments := []SLAReportSegment{}
+	for _, segment := range r.Timeline {
+		start, end := segment.StartTime, segment.EndTime
+		if start < r.PeriodStart {
+			start = r.PeriodStart
+		}
+		if end > r.PeriodEnd {
+			end = r.PeriodEnd
+		}
+		for _, remain := range subtractRanges(start, end, excluded) {
+			segments = append(segments, SLAReportSegment{remain[0], remain[1], segment.State})
+		}
+	}
+	// The excluded ranges within the period are the complement of the remains of the period
+	for _, excludedRange := range subtractRanges(r.PeriodStart, r.PeriodEnd, subtractRanges(r.PeriodStart, r.PeriodEnd, excluded)) {
+		segments = append(segments, SLAReportSegment{excludedRange[0], excludedRange[1], REPORT_STATE_EXCLUDED})
+	}
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].StartTime < segments[j].StartTime
+	})
+	merged := []SLAReportSegment{}
+	for _, segment := range segments {
+		if last := len(merged) - 1; last >= 0 && merged[last].State == segment.State && merged[last].EndTime == segment.StartTime {
+			merged[last].EndTime = segment.EndTime
+			continue
+		}
+		merged = append(merged, segment)
+	}
+	return merged
+}
+
+// Outages returns the segments which are neither up nor excluded.
+func (r *SLAReport) Outages() []SLAReportSegment {
+	outages := []SLAReportSegment{}
+	for _, segment := range r.Segments() {
+		if segment.State != REPORT_STATE_UP && segment.State != REPORT_STATE_EXCLUDED {
+			outages = append(outages, segment)
+		}
+	}
+	return outages
+}
+
+// TimelineOffset returns the ratio of the time between the period start and the segment start
+// to the period duration, ie: the position of the segment on the timeline.
+func (r *SLAReport) TimelineOffset(segment SLAReportSegment) float64 {
+	return float64(segment.StartTime-r.PeriodStart) / float64(r.PeriodEnd-r.PeriodStart)
+}
+
+// TimelineRatio returns the ratio of the segment duration to the period duration.
+func (r *SLAReport) TimelineRatio(segment SLAReportSegment) float64 {
+	return float64(segment.Duration()) / float64(r.PeriodEnd-r.PeriodStart)
+}
+
+// FormatTime formats the unix timestamp in the report location.
+func (r *SLAReport) FormatTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).In(r.location()).Format("2006-01-02 15:04:05")
+}
+
+// FormatReportDuration formats the duration in seconds as h:mm:ss, where hours may exceed 24.
+func FormatReportDuration(seconds int64) string {
+	sign := ""
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%v%d:%02d:%02d", sign, seconds/3600, seconds%3600/60, seconds%60)
+}
+
+func newSLAReportChronology(chronology Bakti1UptimeChronology) SLAReportChronology {
+	return SLAReportChronology{
+		StartTime:           chronology.StartTimestamps,
+		EndTime:             chronology.EndTimestamps,
+		UptimeValue:         chronology.UptimeValue,
+		Status:              chronology.Status,
+		StatusName:          BaktiStatusName(chronology.Status),
+		LinkFailureDuration: chronology.LinkFailureDuration,
+		RestitutionDuration: chronology.RestitutionDuration,
+	}
+}
+
+// HasSqf tells whether the chronology of the report has SQF data.
+func (r *SLAReport) HasSqf() bool {
+	for _, chronology := range r.Chronologies {
+		if chronology.SqfStatusName != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+var reportPenalty = slacalc.PenaltySchedule{
+	Kind: slacalc.PenaltyPercentage,
+	Tiers: []slacalc.PenaltyTier{
+		{Name: "minor", MinAvailability: 0.7, MaxAvailability: 0.9, Credit: 0.1},
+		{Name: "major", MinAvailability: 0, MaxAvailability: 0.7, Credit: 0.25},
+	},
+}
+
+func newBaktiSLAReport(t *testing.T) *slacalc.SLAReport {
+	uptimeVals := []int{}
+	timestamps := []int64{}
+	exceptions := []bool{}
+	for _, val := range uptimeBaktiSeriesData {
+		uptimeVals = append(uptimeVals, val.Value)
+		timestamps = append(timestamps, val.Timestamp)
+		exceptions = append(exceptions, val.Exception)
+	}
+	calc, err := slacalc.NewUptimeSLACalculator(startTimeBakti, endTimeBakti, timestamps, uptimeVals, toleranceDeltaRatio, exceptions)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	report, err := slacalc.NewSLAReport(slacalc.SLAReportOptions{
+		Title:       "Monthly SLA Report",
+		Device:      "site <1>",
+		PeriodStart: startTimeBakti,
+		PeriodEnd:   endTimeBakti,
+		Target:      0.7,
+		Penalty:     &reportPenalty,
+		MonthlyFee:  1000000,
+	})
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	result, err := calc.CalculateFormula(slacalc.FORMULA_SNMP)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	if err := report.AddFormulaResult(result); err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	if err := report.AddBakti1Availability(calc.CalcBakti1Uptime()); err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	return report
+}
+
+func TestSLAReport(t *testing.T) {
+	report := newBaktiSLAReport(t)
+	if len(report.Lines) != 2 {
+		t.Fatalf("The amount of lines is %v instead of 2", len(report.Lines))
+	}
+	bakti1 := report.Lines[1]
+	if bakti1.Formula != slacalc.FORMULA_BAKTI1 || math.Abs(bakti1.Availability-.733333) > ACCURACY {
+		t.Errorf("The bakti1 line is %+v", bakti1)
+	}
+	if !bakti1.Compliant || bakti1.Credit == nil || bakti1.Credit.Tier != "minor" {
+		t.Errorf("The bakti1 line should be compliant with minor credit: %+v", bakti1)
+	}
+	if bakti1.Uptime+bakti1.Downtime != endTimeBakti-startTimeBakti || bakti1.Downtime != 800 {
+		t.Errorf("The bakti1 uptime and downtime are %v, %v", bakti1.Uptime, bakti1.Downtime)
+	}
+	if report.Lines[0].Compliant {
+		t.Errorf("The snmp line should not be compliant: %+v", report.Lines[0])
+	}
+	if len(report.Chronologies) <= 0 || report.Chronologies[0].StatusName != "open" {
+		t.Errorf("The chronology should start with open: %+v", report.Chronologies)
+	}
+	if report.HasSqf() {
+		t.Errorf("The report should have no SQF data")
+	}
+	t.Run("Calculator Period", func(t *testing.T) {
+		uptimeVals := []int{}
+		timestamps := []int64{}
+		for _, val := range uptimeBaktiSeriesData {
+			uptimeVals = append(uptimeVals, val.Value)
+			timestamps = append(timestamps, val.Timestamp)
+		}
+		calc, err := slacalc.NewUptimeSLACalculator(startTimeBakti, endTimeBakti, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		// The report period is longer than the calculator period
+		report, err := slacalc.NewSLAReport(slacalc.SLAReportOptions{PeriodStart: startTimeBakti - 1000, PeriodEnd: endTimeBakti})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if err := report.AddBakti1Availability(calc.CalcBakti1Uptime()); err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		line := report.Lines[0]
+		if line.Uptime != 2200 || line.Downtime != 800 {
+			t.Errorf("The bakti1 uptime and downtime are %v, %v instead of 2200, 800", line.Uptime, line.Downtime)
+		}
+	})
+	t.Run("Invalid Period", func(t *testing.T) {
+		_, err := slacalc.NewSLAReport(slacalc.SLAReportOptions{PeriodStart: 10, PeriodEnd: 10})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package slacalculator
+
+import "fmt"
+
+// RollingAvailability is the availability of a window ending at WindowEnd.
+type RollingAvailability struct {
+	WindowStart  int64
+	WindowEnd    int64
+	Availability float64
+}
+
+// uptimeCursor returns the cumulative counted uptime from the first interval until a time.
+// The queried times should not decrease, so the whole series is walked only once.
+type uptimeCursor struct {
+	intervals  []FormulaInterval
+	index      int
+	cumulative int64
+}
+
+func (c *uptimeCursor) at(t int64) int64 {
+	for c.index < len(c.intervals) && c.intervals[c.index].EndTime <= t {
+		interval := c.intervals[c.index]
+		c.cumulative += interval.EndTime - interval.upStart()
+		c.index++
+	}
+	if c.index >= len(c.intervals) {
+		return c.cumulative
+	}
+	if upStart := c.intervals[c.index].upStart(); t > upStart {
+		return c.cumulative + t - upStart
+	}
+	return c.cumulative
+}
+
+// CalculateRollingAvailability returns, for each formula, the availability series of a sliding window
+// moved by step from the start time until the end time. The first window ends at start time + window.
+// Every generic formula is calculated when no formula is given.
+func (u *UptimeSLACalculator) CalculateRollingAvailability(window, step int64, formulas ...string) (map[string][]RollingAvailability, error) {
+	if window <= 0 || step <= 0 {
+		return nil, fmt.Errorf("window and step should be greater than 0: %v, %v", window, step)
+	}
+	if window > u.endTime-u.startTime {
+		return nil, fmt.Errorf("window is longer than the period: %v", window)
+	}
+	if len(formulas) <= 0 {
+		formulas = u.availableFormulas()
+	}
+	results := map[string][]RollingAvailability{}
+	for _, formula := range formulas {
+		intervals, err := u.countedIntervals(formula)
+		if err != nil {
+			return nil, err
+		}
+		results[formula] = rollAvailability(intervals, u.startTime+window, u.endTime, window, step)
+	}
+	return results, nil
+}
+
+func rollAvailability(intervals []FormulaInterval, firstEnd, lastEnd, window, step int64) []RollingAvailability {
+	// The window is slided by keeping a cursor on each window side
+	head := &uptimeCursor{intervals: intervals}
+	tail := &uptimeCursor{intervals: intervals}
+	series := []RollingAvailability{}
+	for end := firstEnd; end <= lastEnd; end += step {
+		start := end - window
+		counted := head.at(end) - tail.at(start)
+		series = append(series, RollingAvailability{
+			WindowStart:  start,
+			WindowEnd:    end,
+			Availability: float64(counted) / float64(window),
+		})
+	}
+	return series
+}
@@ -0,0 +1,109 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestCalculateRollingAvailability(t *testing.T) {
+	uptimeVals := []int{}
+	timestamps := []int64{}
+	exceptions := []bool{}
+	for _, val := range uptimeSeriesData {
+		uptimeVals = append(uptimeVals, val.Value)
+		timestamps = append(timestamps, val.Timestamp)
+		exceptions = append(exceptions, val.Exception)
+	}
+	calc, err := slacalc.NewUptimeSLACalculator(startTime, endTime, timestamps, uptimeVals, toleranceDeltaRatio, exceptions)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	t.Run("Whole Period", func(t *testing.T) {
+		endTime := endTime + 100
+		calc, err := slacalc.NewUptimeSLACalculator(startTime, endTime, timestamps, uptimeVals, toleranceDeltaRatio, exceptions)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		rolling, err := calc.CalculateRollingAvailability(endTime-startTime, 100)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		expected := map[string]float64{
+			slacalc.FORMULA_SNMP:   expetedSNMPAvailability,
+			slacalc.FORMULA_UPTIME: expetedUptimeAvailability,
+			slacalc.FORMULA_SLA1:   expetedSLA1Availability,
+			slacalc.FORMULA_SLA2:   expetedSLA2Availability,
+		}
+		for formula, expectedAvai := range expected {
+			if len(rolling[formula]) != 1 {
+				t.Fatalf("The amount of %v windows is %v instead of 1", formula, len(rolling[formula]))
+			}
+			if math.Abs(rolling[formula][0].Availability-expectedAvai) >= ACCURACY {
+				t.Errorf("The %v rolling availability is %v instead of %v", formula, rolling[formula][0].Availability, expectedAvai)
+			}
+		}
+	})
+	t.Run("Sliding Window", func(t *testing.T) {
+		rolling, err := calc.CalculateRollingAvailability(200, 50, slacalc.FORMULA_UPTIME)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		series := rolling[slacalc.FORMULA_UPTIME]
+		if len(series) != 57 {
+			t.Fatalf("The amount of windows is %v instead of 57", len(series))
+		}
+		// The uptime 270 at 10500 is spreaded back until 10230, so 10000-10200 is down
+		// and 10200-10400 is up for 170 seconds
+		if math.Abs(series[0].Availability-0.0) >= ACCURACY {
+			t.Errorf("The first window availability is %v instead of 0", series[0].Availability)
+		}
+		if math.Abs(series[4].Availability-0.85) >= ACCURACY {
+			t.Errorf("The window %v-%v availability is %v instead of 0.85", series[4].WindowStart, series[4].WindowEnd, series[4].Availability)
+		}
+		if math.Abs(series[10].Availability-1.0) >= ACCURACY {
+			t.Errorf("The window %v-%v availability is %v instead of 1", series[10].WindowStart, series[10].WindowEnd, series[10].Availability)
+		}
+	})
+	t.Run("Tolerated Excess", func(t *testing.T) {
+		// The counted uptime 105 of 200-300 is within the tolerance, its excess is carried back to 100-200
+		calc, err := slacalc.NewUptimeSLACalculator(0, 400, []int64{100, 200, 300, 400}, []int{100, 150, 255, 355}, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		rolling, err := calc.CalculateRollingAvailability(400, 100, slacalc.FORMULA_UPTIME)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if avai := rolling[slacalc.FORMULA_UPTIME][0].Availability; math.Abs(avai-calc.CalculateUptimeAvailability()) >= ACCURACY {
+			t.Errorf("The rolling availability is %v instead of %v", avai, calc.CalculateUptimeAvailability())
+		}
+		// The excess beyond the first interval has no earlier interval, so it is clipped
+		calc, err = slacalc.NewUptimeSLACalculator(0, 200, []int64{100, 200}, []int{100, 205}, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		rolling, err = calc.CalculateRollingAvailability(200, 100, slacalc.FORMULA_UPTIME)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if avai := calc.CalculateUptimeAvailability(); math.Abs(avai-1.025) >= ACCURACY {
+			t.Errorf("The uptime availability is %v instead of 1.025", avai)
+		}
+		if avai := rolling[slacalc.FORMULA_UPTIME][0].Availability; avai != 1 {
+			t.Errorf("The rolling availability is %v instead of 1", avai)
+		}
+	})
+	t.Run("Invalid Window", func(t *testing.T) {
+		if _, err := calc.CalculateRollingAvailability(0, 100); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := calc.CalculateRollingAvailability(endTime-startTime+1, 100); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := calc.CalculateRollingAvailability(100, 100, "unknown"); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package slacalculator
+
+import (
+	"fmt"
+	"math"
+)
+
+// SLO is the availability target of a formula over a period.
+type SLO struct {
+	Formula string
+	// Target is the availability target, ie: 0.995 for 99.5%.
+	Target float64
+	// Period is the length of the SLO period in seconds, zero means the calculator period.
+	Period int64
+}
+
+// AllowedDowntime returns the error budget of the SLO period in seconds.
+func (s SLO) AllowedDowntime() int64 {
+	return int64(math.Round((1 - s.Target) * float64(s.Period)))
+}
+
+// BurnRateWindow pairs a short and a long window for multi-window burn rate evaluation.
+// It alerts when both burn rates reach the threshold, ie: 1h/6h windows with threshold 6.
+type BurnRateWindow struct {
+	Short     int64
+	Long      int64
+	Threshold float64
+}
+
+// BurnRateEvaluation is the burn rate of a BurnRateWindow at a point of time.
+type BurnRateEvaluation struct {
+	Window        BurnRateWindow
+	ShortBurnRate float64
+	LongBurnRate  float64
+	Alert         bool
+}
+
+// ErrorBudgetPoint explains the error budget of the SLO at a point of time.
+// The ConsumedDowntime only counts the time elapsed until the last timestamp.
+type ErrorBudgetPoint struct {
+	Time             int64
+	AllowedDowntime  int64
+	ConsumedDowntime int64
+	// RemainingBudget is negative when the error budget has been exhausted.
+	RemainingBudget int64
+	RemainingRatio  float64
+	BurnRates       []BurnRateEvaluation
+	// Alert tells whether any burn rate window alerts.
+	Alert bool
+}
+
+// TrackErrorBudget returns the error budget of the SLO from start time until end time on every step.
+// The burn rate is the downtime ratio of a window divided by the allowed downtime ratio, windows
+// which start before the start time are shortened to the start time. The time after the last timestamp
+// has not elapsed yet, so it is neither consumed nor burnt, ie: while tracking an ongoing period.
+func (u *UptimeSLACalculator) TrackErrorBudget(slo SLO, step int64, windows ...BurnRateWindow) ([]ErrorBudgetPoint, error) {
+	if slo.Target <= 0 || slo.Target >= 1 {
+		return nil, fmt.Errorf("target should be between 0 and 1: %v", slo.Target)
+	}
+	if slo.Period < 0 {
+		return nil, fmt.Errorf("period should not be less than 0: %v", slo.Period)
+	}
+	if slo.Period == 0 {
+		slo.Period = u.endTime - u.startTime
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("step should be greater than 0: %v", step)
+	}
+	for _, window := range windows {
+		if window.Short <= 0 || window.Long <= 0 {
+			return nil, fmt.Errorf("burn rate windows should be greater than 0: %v, %v", window.Short, window.Long)
+		}
+	}
+	intervals, err := u.countedIntervals(slo.Formula)
+	if err != nil {
+		return nil, err
+	}
+	allowedDowntime := slo.AllowedDowntime()
+	head := &uptimeCursor{intervals: intervals}
+	shortTails := []*uptimeCursor{}
+	longTails := []*uptimeCursor{}
+	for range windows {
+		shortTails = append(shortTails, &uptimeCursor{intervals: intervals})
+		longTails = append(longTails, &uptimeCursor{intervals: intervals})
+	}
+	observedUntil := u.timestamps[len(u.timestamps)-1]
+	points := []ErrorBudgetPoint{}
+	for t := u.startTime + step; ; t += step {
+		if t > u.endTime {
+			t = u.endTime
+		}
+		elapsed := t
+		if elapsed > observedUntil {
+			elapsed = observedUntil
+		}
+		uptime := head.at(elapsed)
+		point := ErrorBudgetPoint{
+			Time:             t,
+			AllowedDowntime:  allowedDowntime,
+			ConsumedDowntime: elapsed - u.startTime - uptime,
+		}
+		point.RemainingBudget = allowedDowntime - point.ConsumedDowntime
+		if allowedDowntime > 0 {
+			point.RemainingRatio = float64(point.RemainingBudget) / float64(allowedDowntime)
+		}
+		for i, window := range windows {
+			evaluation := BurnRateEvaluation{
+				Window:        window,
+				ShortBurnRate: burnRate(shortTails[i], uptime, u.startTime, elapsed, window.Short, slo.Target),
+				LongBurnRate:  burnRate(longTails[i], uptime, u.startTime, elapsed, window.Long, slo.Target),
+			}
+			evaluation.Alert = evaluation.ShortBurnRate >= window.Threshold && evaluation.LongBurnRate >= window.Threshold
+			point.Alert = point.Alert || evaluation.Alert
+			point.BurnRates = append(point.BurnRates, evaluation)
+		}
+		points = append(points, point)
+		if t >= u.endTime {
+			break
+		}
+	}
+	return points, nil
+}
+
+func burnRate(tail *uptimeCursor, uptime, startTime, t, window int64, target float64) float64 {
+	windowStart := t - window
+	if windowStart < startTime {
+		windowStart = startTime
+	}
+	duration := t - windowStart
+	if duration <= 0 {
+		return 0
+	}
+	downtime := duration - (uptime - tail.at(windowStart))
+	return (float64(downtime) / float64(duration)) / (1 - target)
+}
@@ -0,0 +1,89 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestTrackErrorBudget(t *testing.T) {
+	timestamps := []int64{100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}
+	uptimeVals := []int{100, 200, 300, 400, 0, 0, 100, 200, 300, 400}
+	calc, err := slacalc.NewUptimeSLACalculator(0, 1000, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	slo := slacalc.SLO{Formula: slacalc.FORMULA_UPTIME, Target: 0.9}
+	window := slacalc.BurnRateWindow{Short: 100, Long: 300, Threshold: 6}
+	points, err := calc.TrackErrorBudget(slo, 100, window)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	if len(points) != 10 {
+		t.Fatalf("The amount of points is %v instead of 10", len(points))
+	}
+	last := points[len(points)-1]
+	if last.Time != 1000 || last.AllowedDowntime != 100 || last.ConsumedDowntime != 200 || last.RemainingBudget != -100 {
+		t.Errorf("The last point is %+v instead of 100 allowed and 200 consumed", last)
+	}
+	if math.Abs(last.RemainingRatio-(-1)) >= ACCURACY {
+		t.Errorf("The remaining ratio is %v instead of -1", last.RemainingRatio)
+	}
+	alerted := points[5]
+	if math.Abs(alerted.BurnRates[0].ShortBurnRate-10) >= ACCURACY {
+		t.Errorf("The short burn rate is %v instead of 10", alerted.BurnRates[0].ShortBurnRate)
+	}
+	if math.Abs(alerted.BurnRates[0].LongBurnRate-6.6667) >= ACCURACY {
+		t.Errorf("The long burn rate is %v instead of 6.6667", alerted.BurnRates[0].LongBurnRate)
+	}
+	if !alerted.Alert {
+		t.Errorf("The point at %v should alert", alerted.Time)
+	}
+	if points[3].Alert || points[9].Alert {
+		t.Errorf("The points before and long after the outage should not alert")
+	}
+	t.Run("Uneven Step", func(t *testing.T) {
+		points, err := calc.TrackErrorBudget(slo, 300)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(points) != 4 || points[3].Time != 1000 {
+			t.Errorf("The last point should be at the end time")
+		}
+	})
+	t.Run("Ongoing Period", func(t *testing.T) {
+		// The period ends at 2000, but the series is observed until 1000 only
+		calc, err := slacalc.NewUptimeSLACalculator(0, 2000, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		points, err := calc.TrackErrorBudget(slo, 500, window)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(points) != 4 {
+			t.Fatalf("The amount of points is %v instead of 4", len(points))
+		}
+		expectedConsumed := []int64{100, 200, 200, 200}
+		for i, point := range points {
+			if point.ConsumedDowntime != expectedConsumed[i] {
+				t.Errorf("The consumed downtime at %v is %v instead of %v", point.Time, point.ConsumedDowntime, expectedConsumed[i])
+			}
+		}
+		if points[1].AllowedDowntime != 200 || points[1].RemainingBudget != 0 {
+			t.Errorf("The point at %v is %+v instead of 200 allowed and 0 remaining", points[1].Time, points[1])
+		}
+		if points[2].Alert {
+			t.Errorf("The point at %v should not alert on the unobserved time", points[2].Time)
+		}
+	})
+	t.Run("Invalid SLO", func(t *testing.T) {
+		if _, err := calc.TrackErrorBudget(slacalc.SLO{Formula: slacalc.FORMULA_UPTIME, Target: 1}, 100); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+		if _, err := calc.TrackErrorBudget(slacalc.SLO{Formula: slacalc.FORMULA_SLA2, Target: 0.9}, 100); err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package slacalculator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SNMPLogOptions configures the net-snmp output parser.
+type SNMPLogOptions struct {
+	// TimeLayouts are tried in order to parse the date prefix or the date line, empty means
+	// DefaultSNMPLogTimeLayouts. Unix seconds, ie: the output of date +%s, are always accepted.
+	TimeLayouts []string
+	// Location is used by a time layout without zone, nil means UTC.
+	Location *time.Location
+	// OIDs lists extra OIDs to be read besides sysUpTime, ie: 1.3.6.1.2.1.25.1.1.0 for hrSystemUptime.
+	OIDs []string
+}
+
+// SNMPLogReport explains how the lines of a net-snmp output log are parsed.
+type SNMPLogReport struct {
+	Samples int
+	// Timeouts lists the timestamp of every Timeout: No Response line.
+	Timeouts []int64
+	// IgnoredLines is the amount of lines which are neither a sample nor a date, ie: shell prompts.
+	IgnoredLines int
+}
+
+// DefaultSNMPLogTimeLayouts are the time layouts of the common date prefixes and the date command.
+var DefaultSNMPLogTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02 15:04:05 MST",
+	"2006/01/02 15:04:05",
+	"Mon Jan _2 15:04:05 MST 2006",
+	"Mon Jan _2 15:04:05 2006",
+}
+
+var (
+	snmpLogSampleRegexp  = regexp.MustCompile(`^(?:(.*?)\s+)?(\S+) = Timeticks: (?:\((\d+)\)|(\d+))`)
+	snmpLogTimeoutRegexp = regexp.MustCompile(`^(?:(.*?)\s*)?Timeout: No Response`)
+	snmpLogUnixRegexp    = regexp.MustCompile(`^\d{9,10}$`)
+	// snmpUptimeOIDs are the named and numeric forms of sysUpTime.0 printed by net-snmp
+	snmpUptimeOIDs = []string{
+		"DISMAN-EVENT-MIB::sysUpTimeInstance",
+		"SNMPv2-MIB::sysUpTime.0",
+		"RFC1213-MIB::sysUpTime.0",
+		"sysUpTimeInstance",
+		"sysUpTime.0",
+		"1.3.6.1.2.1.1.3.0",
+	}
+)
+
+func normalizeSNMPOID(oid string) string {
+	oid = strings.TrimPrefix(oid, ".")
+	if strings.HasPrefix(oid, "iso.") {
+		oid = "1." + strings.TrimPrefix(oid, "iso.")
+	}
+	return oid
+}
+
+func (o SNMPLogOptions) acceptsOID(oid string) bool {
+	oid = normalizeSNMPOID(oid)
+	for _, accepted := range snmpUptimeOIDs {
+		if oid == accepted {
+			return true
+		}
+	}
+	for _, accepted := range o.OIDs {
+		if oid == normalizeSNMPOID(accepted) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o SNMPLogOptions) parseTime(text string) (int64, bool) {
+	text = strings.TrimSpace(strings.Trim(strings.TrimSpace(text), "[]:"))
+	if snmpLogUnixRegexp.MatchString(text) {
+		timestamp, err := strconv.ParseInt(text, 10, 64)
+		return timestamp, err == nil
+	}
+	layouts := o.TimeLayouts
+	if len(layouts) <= 0 {
+		layouts = DefaultSNMPLogTimeLayouts
+	}
+	location := o.Location
+	if location == nil {
+		location = time.UTC
+	}
+	for _, layout := range layouts {
+		if timestamp, err := time.ParseInLocation(layout, text, location); err == nil {
+			return timestamp.Unix(), true
+		}
+	}
+	return 0, false
+}
+
+// ReadSNMPLog parses a log of net-snmp snmpget or snmpwalk output of sysUpTime taken in a loop,
+// ie: DISMAN-EVENT-MIB::sysUpTimeInstance = Timeticks: (12345) 0:02:03.45. The time of a sample is
+// its date prefix, or else the last date line, ie: the output of date before snmpget. Timeticks are
+// turned into seconds, and Timeout: No Response lines are imported as zero uptime samples.
+func ReadSNMPLog(r io.Reader, options SNMPLogOptions) (UptimeSeries, SNMPLogReport, error) {
+	report := SNMPLogReport{}
+	series := UptimeSeries{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	hasTime := false
+	var current int64
+	// resolve returns the time of the prefix, or the last date line when there is no prefix
+	resolve := func(prefix string) (int64, error) {
+		if strings.TrimSpace(prefix) != "" {
+			timestamp, ok := options.parseTime(prefix)
+			if !ok {
+				return 0, fmt.Errorf("unknown date prefix of line %v: %v", line, prefix)
+			}
+			return timestamp, nil
+		}
+		if !hasTime {
+			return 0, fmt.Errorf("line %v has no date", line)
+		}
+		return current, nil
+	}
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if match := snmpLogSampleRegexp.FindStringSubmatch(text); match != nil {
+			if !options.acceptsOID(match[2]) {
+				report.IgnoredLines++
+				continue
+			}
+			timestamp, err := resolve(match[1])
+			if err != nil {
+				return UptimeSeries{}, report, err
+			}
+			ticks := match[3]
+			if ticks == "" {
+				ticks = match[4]
+			}
+			value, err := strconv.ParseInt(ticks, 10, 64)
+			if err != nil {
+				return UptimeSeries{}, report, fmt.Errorf("invalid timeticks of line %v: %v", line, ticks)
+			}
+			series.Timestamps = append(series.Timestamps, timestamp)
+			series.UptimeValues = append(series.UptimeValues, int(value/100))
+			report.Samples++
+			continue
+		}
+		if match := snmpLogTimeoutRegexp.FindStringSubmatch(text); match != nil {
+			timestamp, err := resolve(match[1])
+			if err != nil {
+				return UptimeSeries{}, report, err
+			}
+			series.Timestamps = append(series.Timestamps, timestamp)
+			series.UptimeValues = append(series.UptimeValues, 0)
+			report.Timeouts = append(report.Timeouts, timestamp)
+			continue
+		}
+		if timestamp, ok := options.parseTime(text); ok {
+			current = timestamp
+			hasTime = true
+			continue
+		}
+		report.IgnoredLines++
+	}
+	if err := scanner.Err(); err != nil {
+		return UptimeSeries{}, report, fmt.Errorf("failed on reading snmp log: %v", err)
+	}
+	return series, report, nil
+}
+
+// NewCalculatorFromSNMPLog parses the log by ReadSNMPLog, normalizes its series, then returns
+// the uptime calculator object of the series.
+func NewCalculatorFromSNMPLog(r io.Reader, options SNMPLogOptions, startTime, endTime int64, toleranceDeltaRatio float64) (*UptimeSLACalculator, SNMPLogReport, error) {
+	series, report, err := ReadSNMPLog(r, options)
+	if err != nil {
+		return nil, report, err
+	}
+	normalized, _, err := NormalizeSeries(series, DuplicateKeepMax)
+	if err != nil {
+		return nil, report, err
+	}
+	calc, err := normalized.NewCalculator(startTime, endTime, toleranceDeltaRatio)
+	return calc, report, err
+}
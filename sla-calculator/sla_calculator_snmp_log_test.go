@@ -0,0 +1,72 @@
+package slacalculator_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestReadSNMPLog(t *testing.T) {
+	t.Run("Fixture", func(t *testing.T) {
+		file, err := os.Open("testdata/snmpget_sysuptime.log")
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		defer file.Close()
+		series, report, err := slacalc.ReadSNMPLog(file, slacalc.SNMPLogOptions{})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if series.Exceptions != nil {
+			t.Errorf("The exceptions should be nil as net-snmp output has no exception data")
+		}
+		expectedTimestamps := []int64{1700000000, 1700000060, 1700000120, 1700000180, 1700000240, 1700000300, 1700000420}
+		expectedValues := []int{1000, 0, 12, 72, 132, 0, 253}
+		if len(series.Timestamps) != len(expectedTimestamps) {
+			t.Fatalf("The amount of samples is %v instead of %v: %+v", len(series.Timestamps), len(expectedTimestamps), series)
+		}
+		for i := range expectedTimestamps {
+			if series.Timestamps[i] != expectedTimestamps[i] || series.UptimeValues[i] != expectedValues[i] {
+				t.Errorf("The sample %v is %v, %v instead of %v, %v", i, series.Timestamps[i], series.UptimeValues[i], expectedTimestamps[i], expectedValues[i])
+			}
+		}
+		if report.Samples != 5 || len(report.Timeouts) != 2 {
+			t.Errorf("The report is %+v", report)
+		}
+		if report.IgnoredLines != 3 {
+			t.Errorf("The amount of ignored lines is %v instead of 3", report.IgnoredLines)
+		}
+	})
+	t.Run("Extra OID", func(t *testing.T) {
+		input := "1700000420 HOST-RESOURCES-MIB::hrSystemUptime.0 = Timeticks: (25234) 0:04:12.34\n"
+		series, _, err := slacalc.ReadSNMPLog(strings.NewReader(input), slacalc.SNMPLogOptions{OIDs: []string{"HOST-RESOURCES-MIB::hrSystemUptime.0"}})
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if len(series.UptimeValues) != 1 || series.UptimeValues[0] != 252 {
+			t.Errorf("The series is %+v", series)
+		}
+	})
+	t.Run("Missing Date", func(t *testing.T) {
+		_, _, err := slacalc.ReadSNMPLog(strings.NewReader("sysUpTimeInstance = Timeticks: (100) 0:00:01.00\n"), slacalc.SNMPLogOptions{})
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+	t.Run("Calculator", func(t *testing.T) {
+		file, err := os.Open("testdata/snmpget_sysuptime.log")
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		defer file.Close()
+		calc, _, err := slacalc.NewCalculatorFromSNMPLog(file, slacalc.SNMPLogOptions{}, 1699999940, 1700000480, 0.9)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if availability := calc.CalculateSNMPAvailability(); availability <= 0 || availability >= 1 {
+			t.Errorf("The SNMP availability %v should be between 0 and 1", availability)
+		}
+	})
+}
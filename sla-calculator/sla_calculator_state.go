@@ -0,0 +1,74 @@
+package slacalculator
+
+const (
+	// REASON_COUNTER_INCREASED is the reason of an interval whose uptime counter increases.
+	REASON_COUNTER_INCREASED = "counter increased"
+	// REASON_COUNTER_RESET is the reason of an interval whose uptime counter is reset, or
+	// the uptime is spreaded back from the next counter after a reset.
+	REASON_COUNTER_RESET = "counter reset"
+	// REASON_ZERO_VALUE is the reason of an interval whose uptime value is zero.
+	REASON_ZERO_VALUE = "zero value"
+	// REASON_TRAILING_OPEN is the reason of an interval after the last non zero uptime value.
+	REASON_TRAILING_OPEN = "trailing open"
+)
+
+// StateInterval explains the state of the time range covered by an uptime series data.
+type StateInterval struct {
+	StartTime int64
+	EndTime   int64
+	State     string
+	// CountedSeconds is the uptime counted in the interval by CalculateUptimeAvailability.
+	CountedSeconds int64
+	Reason         string
+}
+
+// Duration returns the length of the interval in seconds.
+func (s StateInterval) Duration() int64 {
+	return s.EndTime - s.StartTime
+}
+
+// GetUptimeStateIntervals returns the state of every uptime series data, as GetUptimeStateSeriesData does,
+// along with the time range it covers, its counted uptime and the reason of the state. The time range
+// between the last timestamp and the end time is appended as a trailing open interval.
+func (u *UptimeSLACalculator) GetUptimeStateIntervals() []StateInterval {
+	timestamps := u.timestamps
+	uptimeValues := u.uptimeValues
+	startTime := u.startTime
+	endTime := u.endTime
+	toleranceDeltaRatio := u.toleranceDeltaRatio
+	_, countedVals := transformToSpreadedUptime(startTime, endTime, timestamps, uptimeValues, toleranceDeltaRatio)
+	states := u.GetUptimeStateSeriesData()
+	intervals := []StateInterval{}
+	for i := range timestamps {
+		interval := StateInterval{
+			StartTime:      startTime,
+			EndTime:        timestamps[i],
+			State:          states[i],
+			CountedSeconds: countedVals[i],
+		}
+		if i > 0 {
+			interval.StartTime = timestamps[i-1]
+		}
+		increased := uptimeValues[i] > 0 && (i == 0 || uptimeValues[i] > uptimeValues[i-1])
+		switch {
+		case states[i] == STATE_OPEN:
+			interval.Reason = REASON_TRAILING_OPEN
+		case countedVals[i] > 0 && increased:
+			interval.Reason = REASON_COUNTER_INCREASED
+		case uptimeValues[i] <= 0 && countedVals[i] <= 0:
+			interval.Reason = REASON_ZERO_VALUE
+		default:
+			interval.Reason = REASON_COUNTER_RESET
+		}
+		intervals = append(intervals, interval)
+	}
+	if endTime > timestamps[len(timestamps)-1] {
+		intervals = append(intervals, StateInterval{
+			StartTime: timestamps[len(timestamps)-1],
+			EndTime:   endTime,
+			State:     STATE_OPEN,
+			Reason:    REASON_TRAILING_OPEN,
+		})
+	}
+	return intervals
+}
@@ -0,0 +1,58 @@
+package slacalculator_test
+
+import (
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestGetUptimeStateIntervals(t *testing.T) {
+	endTime := endTime + 100
+	uptimeVals := []int{}
+	timestamps := []int64{}
+	exceptions := []bool{}
+	for _, val := range uptimeSeriesData {
+		uptimeVals = append(uptimeVals, val.Value)
+		timestamps = append(timestamps, val.Timestamp)
+		exceptions = append(exceptions, val.Exception)
+	}
+	calc, err := slacalc.NewUptimeSLACalculator(startTime, endTime, timestamps, uptimeVals, toleranceDeltaRatio, exceptions)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	intervals := calc.GetUptimeStateIntervals()
+	if len(intervals) != len(timestamps)+1 {
+		t.Fatalf("The amount of intervals is %v instead of %v", len(intervals), len(timestamps)+1)
+	}
+	if intervals[0].StartTime != startTime || intervals[len(intervals)-1].EndTime != endTime {
+		t.Errorf("The intervals should cover %v to %v", startTime, endTime)
+	}
+	reasonLen := map[string]int{}
+	var counted int64
+	for i, interval := range intervals {
+		reasonLen[interval.Reason]++
+		counted += interval.CountedSeconds
+		if i > 0 && interval.StartTime != intervals[i-1].EndTime {
+			t.Errorf("The interval %v does not continue the previous one", i)
+		}
+	}
+	expectedReasonLen := map[string]int{
+		slacalc.REASON_COUNTER_INCREASED: 17,
+		slacalc.REASON_COUNTER_RESET:     5,
+		slacalc.REASON_ZERO_VALUE:        5,
+		slacalc.REASON_TRAILING_OPEN:     4,
+	}
+	for reason, expected := range expectedReasonLen {
+		if reasonLen[reason] != expected {
+			t.Errorf("The amount of %v reason is %v instead of %v", reason, reasonLen[reason], expected)
+		}
+	}
+	uptime, _ := calc.GetTotalUptimeAndDowntime()
+	if counted != uptime {
+		t.Errorf("The counted seconds is %v instead of %v", counted, uptime)
+	}
+	summary := calc.CalculateAvailabilitySummary()
+	if summary.Open != 400 {
+		t.Errorf("The open duration is %v instead of 400", summary.Open)
+	}
+}
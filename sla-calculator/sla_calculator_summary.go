@@ -0,0 +1,40 @@
+package slacalculator
+
+// AvailabilitySummary holds the result of every generic SLA calculation of a calculator.
+type AvailabilitySummary struct {
+	SNMPAvailability   float64
+	UptimeAvailability float64
+	SLA1Availability   float64
+	// SLA2Availability is DEFAULT_FLOAT_VALUE when the calculator has no exceptions.
+	SLA2Availability float64
+	Uptime           int64
+	Downtime         int64
+	// Open is the duration of the open state intervals, which is a part of Downtime.
+	Open       int64
+	ClockDrift ClockDrift
+}
+
+// CalculateAvailabilitySummary returns the result of every generic SLA calculation at once.
+func (u *UptimeSLACalculator) CalculateAvailabilitySummary() AvailabilitySummary {
+	summary := AvailabilitySummary{
+		SNMPAvailability:   u.CalculateSNMPAvailability(),
+		UptimeAvailability: u.CalculateUptimeAvailability(),
+		SLA1Availability:   u.CalculateSLA1Availability(),
+		SLA2Availability:   DEFAULT_FLOAT_VALUE,
+	}
+	if u.exceptions != nil {
+		summary.SLA2Availability = u.CalculateSLA2Availability()
+	}
+	summary.Uptime, summary.Downtime = u.GetTotalUptimeAndDowntime()
+	for _, interval := range u.GetUptimeStateIntervals() {
+		if interval.State == STATE_OPEN {
+			summary.Open += interval.Duration()
+		}
+	}
+	if u.clockDrift != nil {
+		summary.ClockDrift = *u.clockDrift
+	} else {
+		summary.ClockDrift = u.EstimateClockDrift()
+	}
+	return summary
+}
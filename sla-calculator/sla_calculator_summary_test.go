@@ -0,0 +1,58 @@
+package slacalculator_test
+
+import (
+	"math"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestCalculateAvailabilitySummary(t *testing.T) {
+	uptimeVals := []int{}
+	timestamps := []int64{}
+	exceptions := []bool{}
+	for _, val := range uptimeSeriesData {
+		uptimeVals = append(uptimeVals, val.Value)
+		timestamps = append(timestamps, val.Timestamp)
+		exceptions = append(exceptions, val.Exception)
+	}
+	t.Run("With Exceptions", func(t *testing.T) {
+		calc, err := slacalc.NewUptimeSLACalculator(startTime, endTime, timestamps, uptimeVals, toleranceDeltaRatio, exceptions)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		summary := calc.CalculateAvailabilitySummary()
+		expected := map[string][2]float64{
+			"SNMP":   {summary.SNMPAvailability, calc.CalculateSNMPAvailability()},
+			"Uptime": {summary.UptimeAvailability, calc.CalculateUptimeAvailability()},
+			"SLA 1":  {summary.SLA1Availability, calc.CalculateSLA1Availability()},
+			"SLA 2":  {summary.SLA2Availability, calc.CalculateSLA2Availability()},
+		}
+		for name, avai := range expected {
+			if math.Abs(avai[0]-avai[1]) >= ACCURACY {
+				t.Errorf("The %v Availability is %v instead of %v", name, avai[0], avai[1])
+			}
+		}
+		uptime, downtime := calc.GetTotalUptimeAndDowntime()
+		if summary.Uptime != uptime || summary.Downtime != downtime {
+			t.Errorf("The uptime and downtime are %v and %v instead of %v and %v", summary.Uptime, summary.Downtime, uptime, downtime)
+		}
+		if summary.ClockDrift.Corrected {
+			t.Errorf("The clock drift of the summary should not be corrected")
+		}
+	})
+	t.Run("Without Exceptions", func(t *testing.T) {
+		calc, err := slacalc.NewUptimeSLACalculator(startTime, endTime, timestamps, uptimeVals, toleranceDeltaRatio, nil)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		summary := calc.CalculateAvailabilitySummary()
+		if summary.SLA2Availability != slacalc.DEFAULT_FLOAT_VALUE {
+			t.Errorf("The SLA 2 Availability without exceptions is %v instead of %v", summary.SLA2Availability, slacalc.DEFAULT_FLOAT_VALUE)
+		}
+		corrected, _ := calc.CorrectClockDrift()
+		if !corrected.CalculateAvailabilitySummary().ClockDrift.Corrected {
+			t.Errorf("The summary should carry the corrected clock drift")
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package slacalculator
+
+import "sort"
+
+// timeSpan is a continuous up or down time range of a formula timeline.
+type timeSpan struct {
+	start int64
+	end   int64
+	up    bool
+}
+
+// timelineOfIntervals converts the counted intervals into consecutive up and down spans,
+// the counted uptime of every interval is placed at the end of the interval.
+func timelineOfIntervals(intervals []FormulaInterval) []timeSpan {
+	spans := []timeSpan{}
+	for _, interval := range intervals {
+		upStart := interval.upStart()
+		spans = appendSpan(spans, timeSpan{interval.StartTime, upStart, false})
+		spans = appendSpan(spans, timeSpan{upStart, interval.EndTime, true})
+	}
+	return spans
+}
+
+// appendSpan appends the span, merging it to the last span with the same state.
+func appendSpan(spans []timeSpan, span timeSpan) []timeSpan {
+	if span.end <= span.start {
+		return spans
+	}
+	if last := len(spans) - 1; last >= 0 && spans[last].up == span.up && spans[last].end == span.start {
+		spans[last].end = span.end
+		return spans
+	}
+	return append(spans, span)
+}
+
+// clipTimeline returns the part of the spans between start and end.
+func clipTimeline(spans []timeSpan, start, end int64) []timeSpan {
+	clipped := []timeSpan{}
+	for _, span := range spans {
+		if span.start < start {
+			span.start = start
+		}
+		if span.end > end {
+			span.end = end
+		}
+		clipped = appendSpan(clipped, span)
+	}
+	return clipped
+}
+
+// mergeRanges sorts and merges overlapping [start, end) ranges.
+func mergeRanges(ranges [][2]int64) [][2]int64 {
+	sorted := append([][2]int64{}, ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][0] < sorted[j][0]
+	})
+	merged := [][2]int64{}
+	for _, r := range sorted {
+		if r[1] <= r[0] {
+			continue
+		}
+		if last := len(merged) - 1; last >= 0 && r[0] <= merged[last][1] {
+			if r[1] > merged[last][1] {
+				merged[last][1] = r[1]
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// subtractRanges returns the parts of [start, end) which are not covered by the merged ranges.
+func subtractRanges(start, end int64, merged [][2]int64) [][2]int64 {
+	remains := [][2]int64{}
+	for _, r := range merged {
+		if r[1] <= start || r[0] >= end {
+			continue
+		}
+		if r[0] > start {
+			remains = append(remains, [2]int64{start, r[0]})
+		}
+		start = r[1]
+	}
+	if start < end {
+		remains = append(remains, [2]int64{start, end})
+	}
+	return remains
+}
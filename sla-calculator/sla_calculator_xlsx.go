@@ -0,0 +1,264 @@
+package slacalculator
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// The cell styles of xlsxStyles, in the order of its cellXfs
+const (
+	xlsxStyleGeneral = iota
+	xlsxStyleBold
+	xlsxStylePercent
+	xlsxStyleDuration
+	xlsxStyleDateTime
+	xlsxStyleAmount
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/worksheets/sheet2.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>
+<sheet name="Summary" sheetId="1" r:id="rId1"/>
+<sheet name="Chronology" sheetId="2" r:id="rId2"/>
+</sheets>
+</workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>
+<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="3">
+<numFmt numFmtId="164" formatCode="[h]:mm:ss"/>
+<numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm:ss"/>
+<numFmt numFmtId="166" formatCode="0.000%"/>
+</numFmts>
+<fonts count="2">
+<font><sz val="11"/><name val="Calibri"/></font>
+<font><b/><sz val="11"/><name val="Calibri"/></font>
+</fonts>
+<fills count="2">
+<fill><patternFill patternType="none"/></fill>
+<fill><patternFill patternType="gray125"/></fill>
+</fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="6">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="166" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+</cellXfs>
+<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>
+</styleSheet>`
+
+// xlsxCell is a string, a number or an empty cell when value is nil.
+type xlsxCell struct {
+	value interface{}
+	style int
+}
+
+func xlsxText(text string) xlsxCell {
+	return xlsxCell{text, xlsxStyleGeneral}
+}
+
+func xlsxHeader(text string) xlsxCell {
+	return xlsxCell{text, xlsxStyleBold}
+}
+
+func xlsxNumber(number float64, style int) xlsxCell {
+	return xlsxCell{number, style}
+}
+
+// xlsxDuration returns a duration cell, which Excel counts in days.
+func xlsxDuration(seconds int64) xlsxCell {
+	return xlsxCell{float64(seconds) / 86400, xlsxStyleDuration}
+}
+
+// xlsxDateTime returns the Excel serial date cell of the unix timestamp in the location.
+func xlsxDateTime(timestamp int64, location *time.Location) xlsxCell {
+	_, offset := time.Unix(timestamp, 0).In(location).Zone()
+	return xlsxCell{float64(timestamp+int64(offset))/86400 + 25569, xlsxStyleDateTime}
+}
+
+func xlsxColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+func writeXLSXSheet(w io.Writer, rows [][]xlsxCell) error {
+	buf := bytes.Buffer{}
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&buf, `<row r="%v">`, i+1)
+		for j, cell := range row {
+			reference := xlsxColumnName(j) + strconv.Itoa(i+1)
+			switch value := cell.value.(type) {
+			case nil:
+				continue
+			case string:
+				fmt.Fprintf(&buf, `<c r="%v" s="%v" t="inlineStr"><is><t xml:space="preserve">`, reference, cell.style)
+				if err := xml.EscapeText(&buf, []byte(value)); err != nil {
+					return err
+				}
+				buf.WriteString(`</t></is></c>`)
+			case float64:
+				fmt.Fprintf(&buf, `<c r="%v" s="%v"><v>%v</v></c>`, reference, cell.style, strconv.FormatFloat(value, 'g', -1, 64))
+			default:
+				return fmt.Errorf("unsupported cell value: %v", value)
+			}
+		}
+		buf.WriteString(`</row>`)
+	}
+	buf.WriteString(`</sheetData></worksheet>`)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func (r *SLAReport) xlsxSummaryRows() [][]xlsxCell {
+	location := r.location()
+	rows := [][]xlsxCell{
+		{xlsxHeader(r.Title)},
+		{xlsxHeader("Device"), xlsxText(r.Device)},
+	}
+	if r.Contract != "" {
+		rows = append(rows, []xlsxCell{xlsxHeader("Contract"), xlsxText(r.Contract)})
+	}
+	rows = append(rows, [][]xlsxCell{
+		{xlsxHeader("Period Start"), xlsxDateTime(r.PeriodStart, location)},
+		{xlsxHeader("Period End"), xlsxDateTime(r.PeriodEnd, location)},
+		{xlsxHeader("Period Duration"), xlsxDuration(r.PeriodEnd - r.PeriodStart)},
+		{},
+		{
+			xlsxHeader("Formula"), xlsxHeader("Availability"), xlsxHeader("Target"), xlsxHeader("Compliance"),
+			xlsxHeader("Uptime"), xlsxHeader("Downtime"), xlsxHeader("Penalty Tier"), xlsxHeader("Credit Ratio"),
+			xlsxHeader("Penalty Amount"),
+		},
+	}...)
+	for _, line := range r.Lines {
+		compliance := "Not Compliant"
+		if line.Compliant {
+			compliance = "Compliant"
+		}
+		row := []xlsxCell{
+			xlsxText(line.Formula),
+			xlsxNumber(line.Availability, xlsxStylePercent),
+			xlsxNumber(line.Target, xlsxStylePercent),
+			xlsxText(compliance),
+			xlsxDuration(line.Uptime),
+			xlsxDuration(line.Downtime),
+		}
+		if line.Credit != nil {
+			row = append(row,
+				xlsxText(line.Credit.Tier),
+				xlsxNumber(line.Credit.CreditRatio, xlsxStylePercent),
+				xlsxNumber(line.Credit.Amount, xlsxStyleAmount),
+			)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (r *SLAReport) xlsxChronologyRows() [][]xlsxCell {
+	location := r.location()
+	hasSqf := r.HasSqf()
+	header := []xlsxCell{
+		xlsxHeader("Start"), xlsxHeader("End"), xlsxHeader("Duration"), xlsxHeader("Uptime Value"),
+		xlsxHeader("Status"), xlsxHeader("Link Failure"), xlsxHeader("Restitution"),
+	}
+	if hasSqf {
+		header = append(header, xlsxHeader("SQF Status"), xlsxHeader("SQF Value"), xlsxHeader("Rain Quota"))
+	}
+	rows := [][]xlsxCell{header}
+	for _, chronology := range r.Chronologies {
+		row := []xlsxCell{
+			xlsxDateTime(chronology.StartTime, location),
+			xlsxDateTime(chronology.EndTime, location),
+			xlsxDuration(chronology.Duration()),
+			xlsxNumber(float64(chronology.UptimeValue), xlsxStyleGeneral),
+			xlsxText(chronology.StatusName),
+			xlsxDuration(chronology.LinkFailureDuration),
+			xlsxDuration(chronology.RestitutionDuration),
+		}
+		if hasSqf {
+			// The rain quota is -1 when it is not applied, which is left blank
+			rainQuota := xlsxCell{}
+			if chronology.RainQuota >= 0 {
+				rainQuota = xlsxDuration(chronology.RainQuota)
+			}
+			row = append(row,
+				xlsxText(chronology.SqfStatusName),
+				xlsxNumber(chronology.SqfValue, xlsxStyleGeneral),
+				rainQuota,
+			)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteXLSX writes the report as an Excel workbook with a Summary sheet, ie: availability, target,
+// compliance and penalty of every formula, and a Chronology sheet of the BAKTI chronology. Timestamps
+// are Excel dates in the report location and durations are formatted as [h]:mm:ss.
+func (r *SLAReport) WriteXLSX(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+		rows    [][]xlsxCell
+	}{
+		{"[Content_Types].xml", xlsxContentTypes, nil},
+		{"_rels/.rels", xlsxRootRels, nil},
+		{"xl/workbook.xml", xlsxWorkbook, nil},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels, nil},
+		{"xl/styles.xml", xlsxStyles, nil},
+		{"xl/worksheets/sheet1.xml", "", r.xlsxSummaryRows()},
+		{"xl/worksheets/sheet2.xml", "", r.xlsxChronologyRows()},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed on creating %v: %v", part.name, err)
+		}
+		if part.rows != nil {
+			err = writeXLSXSheet(file, part.rows)
+		} else {
+			_, err = io.WriteString(file, part.content)
+		}
+		if err != nil {
+			return fmt.Errorf("failed on writing %v: %v", part.name, err)
+		}
+	}
+	return archive.Close()
+}
@@ -0,0 +1,86 @@
+package slacalculator_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	report := newBaktiSLAReport(t)
+	report.Contract = "Contract <A>"
+	// The rain quota is not applied to the link failures
+	linkFailureRow := 0
+	for i := range report.Chronologies {
+		report.Chronologies[i].SqfStatusName = "sqf >= 7.1"
+		report.Chronologies[i].RainQuota = 300
+		if report.Chronologies[i].Status == slacalc.BaktiLinkFailure {
+			report.Chronologies[i].RainQuota = -1
+			linkFailureRow = i + 2
+		}
+	}
+	buf := bytes.Buffer{}
+	if err := report.WriteXLSX(&buf); err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		content, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		// Every part should be well formed
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("The part %v is not well formed: %v", file.Name, err)
+			}
+		}
+		parts[file.Name] = string(content)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("The part %v is missing", name)
+		}
+	}
+	summary := parts["xl/worksheets/sheet1.xml"]
+	for _, expected := range []string{"site &lt;1&gt;", "Contract &lt;A&gt;", "Compliance", "Not Compliant", "minor", "bakti1"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("The summary sheet should contain %q", expected)
+		}
+	}
+	if !strings.Contains(parts["xl/styles.xml"], `formatCode="[h]:mm:ss"`) {
+		t.Errorf("The styles should define the duration format")
+	}
+	chronology := parts["xl/worksheets/sheet2.xml"]
+	if strings.Contains(chronology, "<v>-") || strings.Contains(chronology, fmt.Sprintf(`<c r="J%v"`, linkFailureRow)) {
+		t.Errorf("The rain quota of the link failure should be blank")
+	}
+	if !strings.Contains(chronology, `<c r="J2" s="3"><v>0.003472222222222222</v></c>`) {
+		t.Errorf("The rain quota of the first chronology should be 300 seconds")
+	}
+	// The first chronology is open from 10000 to 10100, 100 seconds in days
+	for _, expected := range []string{"link failure", "power failure", `<c r="C2" s="3"><v>0.0011574074074074073</v></c>`} {
+		if !strings.Contains(chronology, expected) {
+			t.Errorf("The chronology sheet should contain %q", expected)
+		}
+	}
+}
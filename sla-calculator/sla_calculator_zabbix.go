@@ -0,0 +1,296 @@
+package slacalculator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ZABBIX_TREND_PERIOD is the period of a Zabbix trend record in seconds.
+const ZABBIX_TREND_PERIOD = 3600
+
+// ZabbixHistoryRecord is a raw value of an unsigned item, ie: a row of history_uint.
+type ZabbixHistoryRecord struct {
+	ItemID string
+	Clock  int64
+	Value  uint64
+}
+
+// ZabbixTrendRecord is the hourly rollup of an unsigned item, ie: a row of trends_uint.
+type ZabbixTrendRecord struct {
+	ItemID   string
+	Clock    int64
+	Num      int
+	ValueMin uint64
+	ValueMax uint64
+}
+
+type zabbixHistoryJSON struct {
+	ItemID string `json:"itemid"`
+	Clock  string `json:"clock"`
+	Value  string `json:"value"`
+}
+
+type zabbixTrendJSON struct {
+	ItemID   string `json:"itemid"`
+	Clock    string `json:"clock"`
+	Num      string `json:"num"`
+	ValueMin string `json:"value_min"`
+	ValueMax string `json:"value_max"`
+}
+
+// decodeZabbixResult decodes either the JSON-RPC response of the Zabbix API or its bare result list.
+func decodeZabbixResult(r io.Reader, result interface{}) error {
+	reader := bufio.NewReader(r)
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return fmt.Errorf("failed on decoding zabbix json: %v", err)
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			break
+		}
+		reader.ReadByte()
+	}
+	b, _ := reader.Peek(1)
+	if b[0] == '[' {
+		if err := json.NewDecoder(reader).Decode(result); err != nil {
+			return fmt.Errorf("failed on decoding zabbix json: %v", err)
+		}
+		return nil
+	}
+	response := struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Data    string `json:"data"`
+		} `json:"error"`
+	}{}
+	if err := json.NewDecoder(reader).Decode(&response); err != nil {
+		return fmt.Errorf("failed on decoding zabbix json: %v", err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("zabbix api error %v: %v %v", response.Error.Code, response.Error.Message, response.Error.Data)
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("failed on decoding zabbix result: %v", err)
+	}
+	return nil
+}
+
+func parseZabbixClock(value string) (int64, error) {
+	clock, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid clock: %v", value)
+	}
+	return clock, nil
+}
+
+func parseZabbixUint(value string) (uint64, error) {
+	number, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid unsigned value: %v", value)
+	}
+	return number, nil
+}
+
+func newZabbixHistoryRecord(itemID, clock, value string) (ZabbixHistoryRecord, error) {
+	record := ZabbixHistoryRecord{ItemID: strings.TrimSpace(itemID)}
+	var err error
+	if record.Clock, err = parseZabbixClock(clock); err != nil {
+		return record, err
+	}
+	record.Value, err = parseZabbixUint(value)
+	return record, err
+}
+
+func newZabbixTrendRecord(itemID, clock, num, valueMin, valueMax string) (ZabbixTrendRecord, error) {
+	record := ZabbixTrendRecord{ItemID: strings.TrimSpace(itemID)}
+	var err error
+	if record.Clock, err = parseZabbixClock(clock); err != nil {
+		return record, err
+	}
+	if record.Num, err = strconv.Atoi(strings.TrimSpace(num)); err != nil {
+		return record, fmt.Errorf("invalid num: %v", num)
+	}
+	if record.ValueMin, err = parseZabbixUint(valueMin); err != nil {
+		return record, err
+	}
+	record.ValueMax, err = parseZabbixUint(valueMax)
+	return record, err
+}
+
+// ReadZabbixHistoryJSON reads the output of the history.get API method with history type 3,
+// either the whole JSON-RPC response or its result list.
+func ReadZabbixHistoryJSON(r io.Reader) ([]ZabbixHistoryRecord, error) {
+	rows := []zabbixHistoryJSON{}
+	if err := decodeZabbixResult(r, &rows); err != nil {
+		return nil, err
+	}
+	records := []ZabbixHistoryRecord{}
+	for i, row := range rows {
+		record, err := newZabbixHistoryRecord(row.ItemID, row.Clock, row.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed on parsing history %v: %v", i, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ReadZabbixTrendsJSON reads the output of the trends.get API method, either the whole JSON-RPC
+// response or its result list.
+func ReadZabbixTrendsJSON(r io.Reader) ([]ZabbixTrendRecord, error) {
+	rows := []zabbixTrendJSON{}
+	if err := decodeZabbixResult(r, &rows); err != nil {
+		return nil, err
+	}
+	records := []ZabbixTrendRecord{}
+	for i, row := range rows {
+		record, err := newZabbixTrendRecord(row.ItemID, row.Clock, row.Num, row.ValueMin, row.ValueMax)
+		if err != nil {
+			return nil, fmt.Errorf("failed on parsing trend %v: %v", i, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readZabbixCSV reads a SQL dump CSV whose columns are in the table order, or named by a header.
+func readZabbixCSV(r io.Reader, delimiter rune, columns []string, fn func(line int, fields []string) error) error {
+	reader := csv.NewReader(r)
+	if delimiter != 0 {
+		reader.Comma = delimiter
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	indexes := make([]int, len(columns))
+	for i := range columns {
+		indexes[i] = i
+	}
+	line := 0
+	for {
+		line++
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed on reading zabbix csv: %v", err)
+		}
+		if line == 1 && strings.TrimSpace(record[0]) == columns[0] {
+			for i, column := range columns {
+				indexes[i] = -1
+				for j, name := range record {
+					if strings.TrimSpace(name) == column {
+						indexes[i] = j
+					}
+				}
+				if indexes[i] < 0 {
+					return fmt.Errorf("zabbix csv has no %v column", column)
+				}
+			}
+			continue
+		}
+		fields := []string{}
+		for i, index := range indexes {
+			if index >= len(record) {
+				return fmt.Errorf("line %v has no %v column", line, columns[i])
+			}
+			fields = append(fields, record[index])
+		}
+		if err := fn(line, fields); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadZabbixHistoryCSV reads a SQL dump CSV of history_uint, whose columns are itemid, clock, value
+// and ns, optionally with a header. Zero delimiter means comma.
+func ReadZabbixHistoryCSV(r io.Reader, delimiter rune) ([]ZabbixHistoryRecord, error) {
+	records := []ZabbixHistoryRecord{}
+	err := readZabbixCSV(r, delimiter, []string{"itemid", "clock", "value"}, func(line int, fields []string) error {
+		record, err := newZabbixHistoryRecord(fields[0], fields[1], fields[2])
+		if err != nil {
+			return fmt.Errorf("failed on parsing line %v: %v", line, err)
+		}
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// ReadZabbixTrendsCSV reads a SQL dump CSV of trends_uint, whose columns are itemid, clock, num,
+// value_min, value_avg and value_max, optionally with a header. Zero delimiter means comma.
+func ReadZabbixTrendsCSV(r io.Reader, delimiter rune) ([]ZabbixTrendRecord, error) {
+	records := []ZabbixTrendRecord{}
+	err := readZabbixCSV(r, delimiter, []string{"itemid", "clock", "num", "value_min", "value_avg", "value_max"}, func(line int, fields []string) error {
+		record, err := newZabbixTrendRecord(fields[0], fields[1], fields[2], fields[3], fields[5])
+		if err != nil {
+			return fmt.Errorf("failed on parsing line %v: %v", line, err)
+		}
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+const zabbixMaxUptime = uint64(^uint(0) >> 1)
+
+// ZabbixItemSeries builds the series of every item, keyed by the item id, from its history and
+// trends. A trend is only used on the hours without any history, ie: after the raw history has
+// expired. It is turned into a sample at the end of the hour, carrying the value_max, or the
+// value_min when the gap between both is larger than the hour, which means the device rebooted.
+// A trend whose sample collides with a history record is dropped as well.
+func ZabbixItemSeries(history []ZabbixHistoryRecord, trends []ZabbixTrendRecord) (map[string]UptimeSeries, error) {
+	type zabbixSample struct {
+		clock int64
+		value uint64
+	}
+	samples := map[string][]zabbixSample{}
+	historyHours := map[string]map[int64]bool{}
+	historyClocks := map[string]map[int64]bool{}
+	for _, record := range history {
+		samples[record.ItemID] = append(samples[record.ItemID], zabbixSample{record.Clock, record.Value})
+		if historyHours[record.ItemID] == nil {
+			historyHours[record.ItemID] = map[int64]bool{}
+			historyClocks[record.ItemID] = map[int64]bool{}
+		}
+		historyHours[record.ItemID][record.Clock-record.Clock%ZABBIX_TREND_PERIOD] = true
+		historyClocks[record.ItemID][record.Clock] = true
+	}
+	for _, record := range trends {
+		hour := record.Clock - record.Clock%ZABBIX_TREND_PERIOD
+		if historyHours[record.ItemID][hour] || historyClocks[record.ItemID][hour+ZABBIX_TREND_PERIOD] {
+			continue
+		}
+		value := record.ValueMax
+		if record.ValueMax < record.ValueMin || record.ValueMax-record.ValueMin > ZABBIX_TREND_PERIOD {
+			value = record.ValueMin
+		}
+		samples[record.ItemID] = append(samples[record.ItemID], zabbixSample{hour + ZABBIX_TREND_PERIOD, value})
+	}
+	seriesMap := map[string]UptimeSeries{}
+	for itemID, itemSamples := range samples {
+		sort.SliceStable(itemSamples, func(i, j int) bool {
+			return itemSamples[i].clock < itemSamples[j].clock
+		})
+		series := UptimeSeries{}
+		for _, sample := range itemSamples {
+			if sample.value > zabbixMaxUptime {
+				return nil, fmt.Errorf("uptime value of item %v overflows: %v", itemID, sample.value)
+			}
+			series.Timestamps = append(series.Timestamps, sample.clock)
+			series.UptimeValues = append(series.UptimeValues, int(sample.value))
+		}
+		seriesMap[itemID] = series
+	}
+	return seriesMap, nil
+}
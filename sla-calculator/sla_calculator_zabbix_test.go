@@ -0,0 +1,99 @@
+package slacalculator_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	slacalc "github.com/haidlir/golang-uptime-sla-calculator/sla-calculator"
+)
+
+func openZabbixFixture(t *testing.T, name string) *os.File {
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	return file
+}
+
+func checkZabbixSeries(t *testing.T, history []slacalc.ZabbixHistoryRecord, trends []slacalc.ZabbixTrendRecord) {
+	seriesMap, err := slacalc.ZabbixItemSeries(history, trends)
+	if err != nil {
+		t.Fatalf("An Error should not be accoured: %v", err)
+	}
+	if len(seriesMap) != 2 {
+		t.Fatalf("The amount of series is %v instead of 2", len(seriesMap))
+	}
+	series := seriesMap["23296"]
+	if series.Exceptions != nil {
+		t.Errorf("The exceptions should be nil as Zabbix has no exception data")
+	}
+	expectedTimestamps := []int64{1699995600, 1699999200, 1699999800, 1700000400, 1700001000}
+	expectedValues := []int{103540, 400, 1000, 1600, 2200}
+	if len(series.Timestamps) != len(expectedTimestamps) {
+		t.Fatalf("The amount of samples is %v instead of %v", len(series.Timestamps), len(expectedTimestamps))
+	}
+	for i := range expectedTimestamps {
+		if series.Timestamps[i] != expectedTimestamps[i] || series.UptimeValues[i] != expectedValues[i] {
+			t.Errorf("The sample %v is %v, %v instead of %v, %v", i, series.Timestamps[i], series.UptimeValues[i], expectedTimestamps[i], expectedValues[i])
+		}
+	}
+	if seriesMap["23297"].UptimeValues[0] != 18446744073 {
+		t.Errorf("The unsigned value is %v instead of 18446744073", seriesMap["23297"].UptimeValues[0])
+	}
+}
+
+func TestZabbixImporter(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		historyFile := openZabbixFixture(t, "zabbix_history.json")
+		defer historyFile.Close()
+		history, err := slacalc.ReadZabbixHistoryJSON(historyFile)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		trendsFile := openZabbixFixture(t, "zabbix_trends.json")
+		defer trendsFile.Close()
+		trends, err := slacalc.ReadZabbixTrendsJSON(trendsFile)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		checkZabbixSeries(t, history, trends)
+	})
+	t.Run("SQL Dump CSV", func(t *testing.T) {
+		historyFile := openZabbixFixture(t, "zabbix_history_uint.csv")
+		defer historyFile.Close()
+		history, err := slacalc.ReadZabbixHistoryCSV(historyFile, 0)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		trendsFile := openZabbixFixture(t, "zabbix_trends_uint.tsv")
+		defer trendsFile.Close()
+		trends, err := slacalc.ReadZabbixTrendsCSV(trendsFile, '\t')
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		checkZabbixSeries(t, history, trends)
+	})
+	t.Run("Reboot Within Trend", func(t *testing.T) {
+		trends := []slacalc.ZabbixTrendRecord{{ItemID: "1", Clock: 1699995600, ValueMin: 200, ValueMax: 106000}}
+		seriesMap, err := slacalc.ZabbixItemSeries(nil, trends)
+		if err != nil {
+			t.Fatalf("An Error should not be accoured: %v", err)
+		}
+		if seriesMap["1"].UptimeValues[0] != 200 || seriesMap["1"].Timestamps[0] != 1699999200 {
+			t.Errorf("The trend sample is %v, %v instead of 1699999200, 200", seriesMap["1"].Timestamps[0], seriesMap["1"].UptimeValues[0])
+		}
+	})
+	t.Run("API Error", func(t *testing.T) {
+		_, err := slacalc.ReadZabbixHistoryJSON(strings.NewReader(`{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"Not authorized."},"id":1}`))
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+	t.Run("Signed Value", func(t *testing.T) {
+		_, err := slacalc.ReadZabbixHistoryCSV(strings.NewReader("23296,1699999200,-1,0\n"), 0)
+		if err == nil {
+			t.Errorf("Error should be occured.")
+		}
+	})
+}